@@ -0,0 +1,212 @@
+package alert
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/diff"
+	"github.com/s-Amine/token-scan/token"
+	"github.com/s-Amine/token-scan/watch"
+)
+
+// DefaultDedupWindow is how long a repeated alert is suppressed by default.
+const DefaultDedupWindow = time.Hour
+
+// Alert is a notification about a token, sent to the configured sinks.
+type Alert struct {
+	Title    string         `json:"title"`
+	Address  string         `json:"address"`
+	Chain    string         `json:"chain,omitempty"`
+	Severity token.Severity `json:"severity"`
+	Verdict  string         `json:"verdict,omitempty"`
+	Time     time.Time      `json:"time"`
+	// Changes are the field changes behind a watch alert.
+	Changes []diff.Change `json:"changes,omitempty"`
+	// Factors are the risk factors behind a verdict alert.
+	Factors []token.RiskFactor `json:"factors,omitempty"`
+}
+
+// FromWatch converts a watch alert.
+func FromWatch(a watch.Alert) Alert {
+	return Alert{
+		Title:    "Token changed",
+		Address:  a.Address,
+		Severity: a.Severity,
+		Verdict:  a.Verdict,
+		Time:     a.ScannedAt,
+		Changes:  a.Changes,
+	}
+}
+
+// FromScan converts the risk verdict of a scan. The alert has the severity
+// of its most severe risk factor; a token without risk factors yields an
+// alert without severity, which only sinks without a filter receive.
+func FromScan(c chain.Chain, address string, info *token.TokenInfo) Alert {
+	a := Alert{Title: "Risky token", Address: address, Chain: c.Name}
+	if info == nil || info.Risk == nil {
+		return a
+	}
+	a.Verdict = info.Risk.Level
+	a.Factors = info.Risk.Factors
+	for _, factor := range info.Risk.Factors {
+		if factor.Severity.Rank() > a.Severity.Rank() {
+			a.Severity = factor.Severity
+		}
+	}
+	return a
+}
+
+// Text formats the alert as plain text: a headline and one line per change
+// or risk factor.
+func (a Alert) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s: %s", strings.ToUpper(string(a.Severity)), a.Title, a.Address)
+	if a.Verdict != "" {
+		fmt.Fprintf(&b, " (verdict %s)", a.Verdict)
+	}
+	for _, change := range a.Changes {
+		fmt.Fprintf(&b, "\n- %s %s: %s -> %s", change.Severity, change.Field, formatValue(change.Old), formatValue(change.New))
+	}
+	for _, factor := range a.Factors {
+		fmt.Fprintf(&b, "\n- %s %s: %s", factor.Severity, factor.Code, factor.Reason)
+	}
+	return b.String()
+}
+
+// key identifies repeated alerts: the same token with the same changes or
+// risk factors.
+func (a Alert) key() string {
+	parts := []string{strings.ToLower(a.Address), a.Chain, a.Title, string(a.Severity)}
+	for _, change := range a.Changes {
+		parts = append(parts, change.Field+"="+formatValue(change.New))
+	}
+	for _, factor := range a.Factors {
+		parts = append(parts, factor.Code)
+	}
+	sort.Strings(parts[4:])
+	return strings.Join(parts, "|")
+}
+
+// formatValue formats a changed value for text output.
+func formatValue(value interface{}) string {
+	if value == nil {
+		return "none"
+	}
+	return fmt.Sprint(value)
+}
+
+// Sink delivers alerts to a destination.
+type Sink interface {
+	Send(ctx context.Context, a Alert) error
+}
+
+// route is a sink with the lowest severity it receives.
+type route struct {
+	name        string
+	sink        Sink
+	minSeverity token.Severity
+}
+
+// Router sends alerts to every sink whose severity filter they pass and
+// suppresses alerts repeated within the dedup window.
+type Router struct {
+	routes      []route
+	dedupWindow time.Duration
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// NewRouter returns a router without sinks. A zero dedupWindow uses
+// DefaultDedupWindow; a negative one disables deduplication.
+func NewRouter(dedupWindow time.Duration) *Router {
+	if dedupWindow == 0 {
+		dedupWindow = DefaultDedupWindow
+	}
+	return &Router{dedupWindow: dedupWindow, seen: map[string]time.Time{}}
+}
+
+// Add routes alerts of at least minSeverity to sink. An empty minSeverity
+// routes every alert.
+func (r *Router) Add(name string, sink Sink, minSeverity token.Severity) {
+	r.routes = append(r.routes, route{name: name, sink: sink, minSeverity: minSeverity})
+}
+
+// Len returns the number of sinks.
+func (r *Router) Len() int {
+	return len(r.routes)
+}
+
+// Send delivers a to the matching sinks. Repeated alerts are dropped, and
+// every sink is tried even when another one fails. An alert only counts as
+// sent once a sink delivered it, so a failed alert is retried next time.
+func (r *Router) Send(ctx context.Context, a Alert) error {
+	if a.Time.IsZero() {
+		a.Time = time.Now().UTC()
+	}
+	// Reserving the alert before delivery keeps concurrent sends of the same
+	// alert from both delivering it
+	if !r.reserve(a, time.Now()) {
+		return nil
+	}
+
+	var errs []string
+	delivered := false
+	for _, route := range r.routes {
+		if a.Severity.Rank() < route.minSeverity.Rank() {
+			continue
+		}
+		if err := route.sink.Send(ctx, a); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", route.name, err))
+			continue
+		}
+		delivered = true
+	}
+	if !delivered {
+		r.release(a)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("error sending alert: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// reserve records a as sent at now, unless it was already sent or is being
+// sent within the dedup window, in which case it returns false.
+func (r *Router) reserve(a Alert, now time.Time) bool {
+	if r.dedupWindow < 0 {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, sent := range r.seen {
+		if now.Sub(sent) >= r.dedupWindow {
+			delete(r.seen, key)
+		}
+	}
+	key := a.key()
+	if _, ok := r.seen[key]; ok {
+		return false
+	}
+	r.seen[key] = now
+	return true
+}
+
+// release forgets the reservation of an alert that no sink delivered.
+func (r *Router) release(a Alert) {
+	if r.dedupWindow < 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.seen, a.key())
+}
@@ -0,0 +1,186 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/s-Amine/token-scan/token"
+)
+
+// countingSink counts deliveries and fails while failing is set.
+type countingSink struct {
+	sent    int
+	failing bool
+}
+
+func (s *countingSink) Send(ctx context.Context, a Alert) error {
+	if s.failing {
+		return errors.New("sink down")
+	}
+	s.sent++
+	return nil
+}
+
+func TestRouterRetriesUndeliveredAlerts(t *testing.T) {
+	sink := &countingSink{failing: true}
+	router := NewRouter(0)
+	router.Add("sink", sink, "")
+	a := Alert{Title: "Risky token", Address: "0xabc", Severity: token.SeverityHigh}
+
+	if err := router.Send(context.Background(), a); err == nil {
+		t.Fatal("got no error from a failing sink")
+	}
+	sink.failing = false
+	if err := router.Send(context.Background(), a); err != nil {
+		t.Fatal(err)
+	}
+	if err := router.Send(context.Background(), a); err != nil {
+		t.Fatal(err)
+	}
+	if sink.sent != 1 {
+		t.Errorf("got %d deliveries, want the failed alert delivered once on retry", sink.sent)
+	}
+}
+
+func TestWebhookSignature(t *testing.T) {
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	webhook := &Webhook{URL: server.URL, Secret: "secret"}
+	if err := webhook.Send(context.Background(), Alert{Title: "Risky token", Address: "0xabc"}); err != nil {
+		t.Fatal(err)
+	}
+
+	timestamp := header.Get(TimestampHeader)
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(sent, 0)) > time.Minute {
+		t.Errorf("got timestamp %q, want the current Unix time", timestamp)
+	}
+	if got, want := header.Get(SignatureHeader), Sign("secret", timestamp, body); got != want {
+		t.Errorf("got signature %s, want %s", got, want)
+	}
+	if Sign("secret", "1700000000", body) == Sign("secret", timestamp, body) {
+		t.Error("signature does not depend on the timestamp")
+	}
+}
+
+func TestRouterDeliversConcurrentDuplicatesOnce(t *testing.T) {
+	sink := &slowSink{delay: 20 * time.Millisecond}
+	router := NewRouter(0)
+	router.Add("sink", sink, "")
+	a := Alert{Title: "Risky token", Address: "0xabc", Severity: token.SeverityHigh}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := router.Send(context.Background(), a); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := sink.count(); got != 1 {
+		t.Errorf("got %d deliveries, want 1", got)
+	}
+}
+
+// slowSink counts deliveries, each taking delay.
+type slowSink struct {
+	delay time.Duration
+	mu    sync.Mutex
+	sent  int
+}
+
+func (s *slowSink) Send(ctx context.Context, a Alert) error {
+	time.Sleep(s.delay)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent++
+	return nil
+}
+
+func (s *slowSink) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sent
+}
+
+func TestWebhookErrorsHideURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	const secret = "T000/B000/XXXXsecretXXXX"
+
+	err := (&Slack{URL: server.URL + "/services/" + secret}).Send(context.Background(), Alert{Title: "Risky token"})
+	if err == nil || strings.Contains(err.Error(), secret) || !strings.Contains(err.Error(), "status 403") {
+		t.Errorf("got error %v, want status 403 without the URL path", err)
+	}
+
+	// Errors of unreachable servers quote the URL too
+	server.Close()
+	err = (&Slack{URL: server.URL + "/services/" + secret}).Send(context.Background(), Alert{Title: "Risky token"})
+	if err == nil || strings.Contains(err.Error(), secret) {
+		t.Errorf("got error %v, want an error without the URL path", err)
+	}
+}
+
+func TestSMTPMessageHeaders(t *testing.T) {
+	s := &SMTP{From: "alerts@example.com", To: []string{"ops@example.com"}}
+	a := Alert{Title: "Risky token\r\nBcc: victim@example.com", Address: "0xabc\nX-Injected: 1", Severity: token.SeverityHigh}
+
+	headers, _, _ := strings.Cut(string(s.message(a)), "\r\n\r\n")
+	for _, line := range strings.Split(headers, "\r\n") {
+		if strings.HasPrefix(line, "Bcc:") || strings.HasPrefix(line, "X-Injected:") {
+			t.Errorf("alert fields added the header %q", line)
+		}
+	}
+	if !strings.Contains(headers, "Subject: [token-scan] high alert: Risky token Bcc: victim@example.com 0xabc X-Injected: 1\r\n") {
+		t.Errorf("got headers %q, want the alert fields on the subject line", headers)
+	}
+}
+
+func TestSMTPSendHonorsDeadline(t *testing.T) {
+	// The server accepts connections but never greets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	s := &SMTP{Addr: listener.Addr().String(), From: "alerts@example.com", To: []string{"ops@example.com"}}
+	start := time.Now()
+	if err := s.Send(ctx, Alert{Title: "Risky token"}); err == nil {
+		t.Fatal("got no error from a silent server")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("send took %v, want it bounded by the context deadline", elapsed)
+	}
+}
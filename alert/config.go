@@ -0,0 +1,88 @@
+package alert
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/s-Amine/token-scan/token"
+)
+
+// Sink types accepted in SinkConfig.Type.
+const (
+	SinkWebhook = "webhook"
+	SinkSlack   = "slack"
+	SinkSMTP    = "smtp"
+)
+
+// Config represents the alerts section of the configuration file.
+type Config struct {
+	// DedupWindow is how long, in seconds, a repeated alert is suppressed;
+	// zero uses DefaultDedupWindow and a negative value disables it.
+	DedupWindow int          `json:"dedup_window,omitempty"`
+	Sinks       []SinkConfig `json:"sinks,omitempty"`
+}
+
+// SinkConfig configures one sink. Fields not used by its type are ignored.
+type SinkConfig struct {
+	Type string `json:"type"`
+	// MinSeverity is the lowest alert severity sent to the sink; empty
+	// sends every alert.
+	MinSeverity token.Severity `json:"min_severity,omitempty"`
+	// Timeout is the delivery timeout in seconds of webhook and Slack sinks.
+	Timeout int `json:"timeout,omitempty"`
+
+	URL    string `json:"url,omitempty"`
+	Secret string `json:"secret,omitempty"`
+
+	Addr     string   `json:"addr,omitempty"`
+	Username string   `json:"username,omitempty"`
+	Password string   `json:"password,omitempty"`
+	From     string   `json:"from,omitempty"`
+	To       []string `json:"to,omitempty"`
+}
+
+// NewRouterFromConfig builds a router with the configured sinks.
+func NewRouterFromConfig(cfg Config) (*Router, error) {
+	router := NewRouter(time.Duration(cfg.DedupWindow) * time.Second)
+
+	for i, sinkCfg := range cfg.Sinks {
+		if sinkCfg.MinSeverity != "" && sinkCfg.MinSeverity.Rank() == 0 {
+			return nil, fmt.Errorf("alert sink %d: invalid min_severity %q", i, sinkCfg.MinSeverity)
+		}
+		sink, err := newSink(sinkCfg)
+		if err != nil {
+			return nil, fmt.Errorf("alert sink %d: %v", i, err)
+		}
+		router.Add(fmt.Sprintf("%s sink %d", sinkCfg.Type, i), sink, sinkCfg.MinSeverity)
+	}
+
+	return router, nil
+}
+
+// newSink creates the sink described by cfg.
+func newSink(cfg SinkConfig) (Sink, error) {
+	var client *http.Client
+	if cfg.Timeout != 0 {
+		client = &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second}
+	}
+
+	switch cfg.Type {
+	case SinkWebhook:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("url is required")
+		}
+		return &Webhook{URL: cfg.URL, Secret: cfg.Secret, Client: client}, nil
+	case SinkSlack:
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("url is required")
+		}
+		return &Slack{URL: cfg.URL, Client: client}, nil
+	case SinkSMTP:
+		if cfg.Addr == "" || cfg.From == "" || len(cfg.To) == 0 {
+			return nil, fmt.Errorf("addr, from and to are required")
+		}
+		return &SMTP{Addr: cfg.Addr, Username: cfg.Username, Password: cfg.Password, From: cfg.From, To: cfg.To}, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", cfg.Type)
+}
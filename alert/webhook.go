@@ -0,0 +1,114 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// SignatureHeader carries the HMAC-SHA256 signature of a webhook request, as
+// "sha256=<hex digest>". The signed message is the TimestampHeader value, a
+// dot and the body, so that a captured request cannot be replayed later.
+const SignatureHeader = "X-Tokenscan-Signature"
+
+// TimestampHeader carries the Unix time, in seconds, a webhook was signed at.
+const TimestampHeader = "X-Tokenscan-Timestamp"
+
+// defaultTimeout bounds the delivery of an alert.
+const defaultTimeout = 10 * time.Second
+
+// Webhook posts alerts as JSON to a URL. When Secret is set the request is
+// signed in the SignatureHeader.
+type Webhook struct {
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+// Send implements Sink.
+func (w *Webhook) Send(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("error marshaling alert: %v", err)
+	}
+
+	header := http.Header{}
+	if w.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		header.Set(TimestampHeader, timestamp)
+		header.Set(SignatureHeader, Sign(w.Secret, timestamp, body))
+	}
+	return post(ctx, w.Client, w.URL, body, header)
+}
+
+// Sign returns the signature of body sent at timestamp with secret, as sent
+// in SignatureHeader.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Slack posts alerts to a Slack-compatible incoming webhook.
+type Slack struct {
+	URL    string
+	Client *http.Client
+}
+
+// Send implements Sink.
+func (s *Slack) Send(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(map[string]string{"text": a.Text()})
+	if err != nil {
+		return fmt.Errorf("error marshaling alert: %v", err)
+	}
+	return post(ctx, s.Client, s.URL, body, nil)
+}
+
+// post sends a JSON body and fails on non-2xx responses. Errors name the host
+// only, as webhook URLs such as Slack's embed their secret in the path.
+func post(ctx context.Context, client *http.Client, endpoint string, body []byte, header http.Header) error {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating request: %v", withoutURL(err))
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request to %s: %v", req.URL.Host, withoutURL(err))
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, res.Body)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("%s returned status %d", req.URL.Host, res.StatusCode)
+	}
+	return nil
+}
+
+// withoutURL strips the URL that net/url and net/http errors quote.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
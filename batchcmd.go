@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/s-Amine/token-scan/alert"
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/envelope"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/token"
+	"github.com/s-Amine/token-scan/watch"
+)
+
+// batchCommand implements the batch command. It scans a list of tokens,
+// prints their envelopes and sends an alert for every token with a risk
+// factor of at least -min-severity.
+func batchCommand(flags *flag.FlagSet) func(args []string) {
+	tokens := flags.String("tokens", "", "Comma-separated token hashes to scan")
+	file := flags.String("file", "", "File listing token hashes to scan, one per line (- for stdin)")
+	concurrency := flags.Int("concurrency", 4, "Number of tokens scanned at once")
+	includeRaw := flags.Bool("include-raw", false, "Attach each provider's full response to the output")
+	minSeverity := flags.String("min-severity", string(watch.DefaultMinSeverity), "Lowest risk factor severity to alert on: low, medium, high or critical")
+	noAlerts := flags.Bool("no-alerts", false, "Do not send alerts")
+	noHistory := flags.Bool("no-history", false, "Do not record the scans in the history store")
+	useFixtures := addFixtureFlags(flags)
+
+	return func(args []string) {
+		addresses, err := tokenList(*tokens, *file)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		addresses = append(addresses, args...)
+		if len(addresses) == 0 {
+			usageError(flags, "-tokens, -file or token hash arguments are required")
+		}
+		if *concurrency < 1 {
+			usageError(flags, "invalid -concurrency %d", *concurrency)
+		}
+		severity := parseSeverity(flags, *minSeverity)
+		c := globals.scanChain
+
+		cfg := loadConfig()
+		useFixtures()
+		router := alertRouter(cfg.Alerts, *noAlerts)
+
+		// Scan the tokens concurrently, keeping the results in address order
+		results := make([]*multiscan.Result, len(addresses))
+		slots := make(chan struct{}, *concurrency)
+		var wg sync.WaitGroup
+		for i, address := range addresses {
+			wg.Add(1)
+			slots <- struct{}{}
+			go func(i int, address string) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				results[i] = multiscan.ScanWithOptions(address, multiscan.Options{Chain: c, IncludeRaw: true})
+				verbosef("Scanned %s (%d/%d)", address, i+1, len(addresses))
+			}(i, address)
+		}
+		wg.Wait()
+
+		envelopes := make([]*envelope.Envelope, len(addresses))
+		for i, address := range addresses {
+			reportProviderErrors(address, results[i])
+			envelopes[i] = envelope.Multiscan(c, address, results[i], *includeRaw)
+			if !*noHistory && !cfg.History.Disabled {
+				recordHistory(cfg.History.Path, "multiscan", c, address, results[i])
+			}
+			sendRiskAlert(router, severity, c, address, results[i])
+		}
+		printResult(envelopes)
+	}
+}
+
+// sendRiskAlert sends the verdict of a scan to the alert sinks when it has a
+// risk factor of at least minSeverity.
+func sendRiskAlert(router *alert.Router, minSeverity token.Severity, c chain.Chain, address string, result *multiscan.Result) {
+	if router.Len() == 0 {
+		return
+	}
+	a := alert.FromScan(c, address, result.Unified)
+	if a.Severity.Rank() < minSeverity.Rank() {
+		return
+	}
+	if err := router.Send(context.Background(), a); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	}
+}
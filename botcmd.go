@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/telegram"
+)
+
+// botCommand implements the bot command. It answers Telegram messages holding
+// token addresses with a risk summary until SIGINT or SIGTERM.
+func botCommand(flags *flag.FlagSet) func(args []string) {
+	baseURL := flags.String("base-url", "", "Telegram Bot API base URL (defaults to the telegram.base_url setting or "+telegram.DefaultBaseURL+")")
+	noHistory := flags.Bool("no-history", false, "Do not record the scans in the history store")
+
+	return func(args []string) {
+		if len(args) != 0 {
+			usageError(flags, "unexpected arguments %q", args)
+		}
+		cfg := loadConfig()
+
+		bot := &telegram.Bot{
+			Config: cfg.Telegram,
+			Chain:  globals.scanChain,
+			OnError: func(err error) {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			},
+		}
+		if *baseURL != "" {
+			bot.BaseURL = *baseURL
+		}
+		if !*noHistory && !cfg.History.Disabled {
+			bot.OnScan = func(c chain.Chain, address string, result *multiscan.Result) {
+				verbosef("Scanned %s on %s", address, c)
+				recordHistory(cfg.History.Path, "multiscan", c, address, result)
+			}
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		infof("Telegram bot started")
+		if err := bot.Run(ctx); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		infof("Telegram bot stopped")
+	}
+}
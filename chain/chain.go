@@ -0,0 +1,70 @@
+package chain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Chain describes a blockchain and how each provider identifies it.
+type Chain struct {
+	// Name is the canonical lowercase name of the chain.
+	Name string `json:"name"`
+	// ID is the EVM chain ID, used by GoPlus and honeypot.is.
+	ID string `json:"id"`
+	// QuickIntel is the chain name used by QuickIntel.
+	QuickIntel string `json:"-"`
+	// Honeypot reports whether honeypot.is supports the chain.
+	Honeypot bool `json:"-"`
+	// Aliases are other names the chain is looked up by.
+	Aliases []string `json:"-"`
+}
+
+// Supported chains.
+var (
+	Ethereum  = Chain{Name: "ethereum", ID: "1", QuickIntel: "eth", Honeypot: true, Aliases: []string{"eth", "mainnet"}}
+	BSC       = Chain{Name: "bsc", ID: "56", QuickIntel: "bsc", Honeypot: true, Aliases: []string{"bnb", "binance"}}
+	Base      = Chain{Name: "base", ID: "8453", QuickIntel: "base", Honeypot: true}
+	Arbitrum  = Chain{Name: "arbitrum", ID: "42161", QuickIntel: "arbitrum", Aliases: []string{"arb"}}
+	Polygon   = Chain{Name: "polygon", ID: "137", QuickIntel: "polygon", Aliases: []string{"matic"}}
+	Avalanche = Chain{Name: "avalanche", ID: "43114", QuickIntel: "avalanche", Aliases: []string{"avax"}}
+)
+
+// Default is the chain scanned when none is given.
+var Default = Ethereum
+
+// All lists the supported chains.
+var All = []Chain{Ethereum, BSC, Base, Arbitrum, Polygon, Avalanche}
+
+// Lookup finds a chain by name, alias or chain ID, ignoring case.
+func Lookup(nameOrID string) (Chain, bool) {
+	nameOrID = strings.ToLower(strings.TrimSpace(nameOrID))
+	for _, c := range All {
+		if nameOrID == c.Name || nameOrID == c.ID {
+			return c, true
+		}
+		for _, alias := range c.Aliases {
+			if nameOrID == alias {
+				return c, true
+			}
+		}
+	}
+	return Chain{}, false
+}
+
+// Parse is like Lookup but returns an error for unknown chains. An empty
+// value yields Default.
+func Parse(nameOrID string) (Chain, error) {
+	if strings.TrimSpace(nameOrID) == "" {
+		return Default, nil
+	}
+	c, ok := Lookup(nameOrID)
+	if !ok {
+		return Chain{}, fmt.Errorf("unknown chain %q", nameOrID)
+	}
+	return c, nil
+}
+
+// String returns the chain name.
+func (c Chain) String() string {
+	return c.Name
+}
@@ -0,0 +1,235 @@
+package compare
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/token"
+)
+
+// Candidate is one of the compared tokens.
+type Candidate struct {
+	Address string `json:"address"`
+	Name    string `json:"name,omitempty"`
+	Symbol  string `json:"symbol,omitempty"`
+	Verdict string `json:"verdict,omitempty"`
+	Score   int    `json:"score"`
+	// Rank orders the candidates from the most likely legitimate (1).
+	Rank int `json:"rank"`
+	// Incomplete is set when a provider failed or reported no data, or the
+	// verdict is unknown, which makes the verdict less reliable.
+	Incomplete bool `json:"incomplete,omitempty"`
+}
+
+// Row is one field of the comparison matrix, with a value per candidate.
+type Row struct {
+	Field  string   `json:"field"`
+	Values []string `json:"values"`
+}
+
+// Comparison is the side-by-side comparison of several tokens.
+type Comparison struct {
+	Candidates []Candidate `json:"candidates"`
+	Rows       []Row       `json:"rows"`
+	// MostLikelyLegitimate is the address of the best ranked candidate, left
+	// empty when no candidate has a complete scan.
+	MostLikelyLegitimate string `json:"most_likely_legitimate,omitempty"`
+}
+
+// row describes a compared field and how to read it.
+type row struct {
+	field string
+	value func(info *token.TokenInfo) string
+}
+
+// rows are the compared fields, in display order.
+var rows = []row{
+	{"token_name", func(t *token.TokenInfo) string { return t.TokenName }},
+	{"token_symbol", func(t *token.TokenInfo) string { return t.TokenSymbol }},
+	{"verdict", func(t *token.TokenInfo) string { return riskLevel(t) }},
+	{"score", func(t *token.TokenInfo) string { return strconv.Itoa(riskScore(t)) }},
+	{"is_honeypot", func(t *token.TokenInfo) string { return yesNo(t.IsHoneypot) }},
+	{"cannot_sell_all", func(t *token.TokenInfo) string { return yesNo(t.CannotSellAll) }},
+	{"buy_tax", func(t *token.TokenInfo) string { return t.BuyTax }},
+	{"sell_tax", func(t *token.TokenInfo) string { return t.SellTax }},
+	{"is_open_source", func(t *token.TokenInfo) string { return yesNo(t.IsOpenSource) }},
+	{"hidden_owner", func(t *token.TokenInfo) string { return yesNo(t.HiddenOwner) }},
+	{"is_mintable", func(t *token.TokenInfo) string { return yesNo(t.IsMintable) }},
+	{"is_blacklisted", func(t *token.TokenInfo) string { return yesNo(t.IsBlacklisted) }},
+	{"transfer_pausable", func(t *token.TokenInfo) string { return yesNo(t.TransferPausable) }},
+	{"ownership.renounced", func(t *token.TokenInfo) string {
+		if t.Ownership == nil {
+			return ""
+		}
+		return yesNo(t.Ownership.Renounced)
+	}},
+	{"upgradeability.upgradeable", func(t *token.TokenInfo) string {
+		if t.Upgradeability == nil {
+			return ""
+		}
+		return yesNo(t.Upgradeability.Upgradeable())
+	}},
+	{"liquidity.liquidity_usd", func(t *token.TokenInfo) string {
+		if t.Liquidity == nil {
+			return ""
+		}
+		return strconv.FormatFloat(t.Liquidity.LiquidityUSD, 'f', 0, 64)
+	}},
+	{"liquidity.age_days", func(t *token.TokenInfo) string {
+		if t.Liquidity == nil || t.Liquidity.CreatedAt == nil {
+			return ""
+		}
+		return strconv.FormatFloat(t.Liquidity.AgeHours/24, 'f', 1, 64)
+	}},
+	{"holders", func(t *token.TokenInfo) string {
+		if n := holderCount(t); n > 0 {
+			return strconv.Itoa(n)
+		}
+		return ""
+	}},
+	{"concentration.top_holders_share", func(t *token.TokenInfo) string {
+		if t.Concentration == nil {
+			return ""
+		}
+		return strconv.FormatFloat(t.Concentration.TopHoldersShare, 'f', -1, 64)
+	}},
+	{"concentration.lp_secured_share", func(t *token.TokenInfo) string {
+		if t.Concentration == nil {
+			return ""
+		}
+		return strconv.FormatFloat(t.Concentration.LPLockedShare+t.Concentration.LPBurnedShare, 'f', -1, 64)
+	}},
+}
+
+// Compare lays out the multiscan results of several tokens side by side and
+// ranks them. Tokens whose scan is complete come first, then incomplete scans
+// with a verdict and last tokens without one; among them the most likely
+// legitimate token has the lowest risk score, and ties go to deeper
+// liquidity, more holders and older pairs. results must be in the order of
+// addresses.
+func Compare(addresses []string, results []*multiscan.Result) (*Comparison, error) {
+	if len(addresses) != len(results) {
+		return nil, fmt.Errorf("got %d results for %d addresses", len(results), len(addresses))
+	}
+
+	comparison := &Comparison{}
+	for i, result := range results {
+		info := result.Unified
+		comparison.Candidates = append(comparison.Candidates, Candidate{
+			Address:    addresses[i],
+			Name:       info.TokenName,
+			Symbol:     info.TokenSymbol,
+			Verdict:    riskLevel(info),
+			Score:      riskScore(info),
+			Incomplete: (result.Raw != nil && len(result.Raw.Errors) > 0) || !completeVerdict(info),
+		})
+	}
+	for _, r := range rows {
+		matrixRow := Row{Field: r.field}
+		for _, result := range results {
+			matrixRow.Values = append(matrixRow.Values, r.value(result.Unified))
+		}
+		comparison.Rows = append(comparison.Rows, matrixRow)
+	}
+
+	order := make([]int, len(results))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return better(comparison.Candidates[order[a]], results[order[a]].Unified, comparison.Candidates[order[b]], results[order[b]].Unified)
+	})
+	for rank, i := range order {
+		comparison.Candidates[i].Rank = rank + 1
+	}
+	if len(order) > 0 && !comparison.Candidates[order[0]].Incomplete {
+		comparison.MostLikelyLegitimate = comparison.Candidates[order[0]].Address
+	}
+
+	return comparison, nil
+}
+
+// better reports whether candidate a is more likely legitimate than b.
+func better(a Candidate, aInfo *token.TokenInfo, b Candidate, bInfo *token.TokenInfo) bool {
+	if a.Incomplete != b.Incomplete {
+		return !a.Incomplete
+	}
+	if aa, ab := assessed(aInfo), assessed(bInfo); aa != ab {
+		return aa
+	}
+	if a.Score != b.Score {
+		return a.Score < b.Score
+	}
+	if la, lb := liquidityUSD(aInfo), liquidityUSD(bInfo); la != lb {
+		return la > lb
+	}
+	if ha, hb := holderCount(aInfo), holderCount(bInfo); ha != hb {
+		return ha > hb
+	}
+	return pairAge(aInfo) > pairAge(bInfo)
+}
+
+// completeVerdict reports whether the verdict is based on the data of every
+// provider.
+func completeVerdict(t *token.TokenInfo) bool {
+	return assessed(t) && !t.Risk.Incomplete
+}
+
+// assessed reports whether the token has a known verdict.
+func assessed(t *token.TokenInfo) bool {
+	return t.Risk != nil && t.Risk.Level != "" && t.Risk.Level != token.VerdictUnknown
+}
+
+// riskLevel returns the verdict level, or an empty string.
+func riskLevel(t *token.TokenInfo) string {
+	if t.Risk == nil {
+		return ""
+	}
+	return t.Risk.Level
+}
+
+// riskScore returns the risk score; unassessed tokens get the maximum.
+func riskScore(t *token.TokenInfo) int {
+	if t.Risk == nil {
+		return 100
+	}
+	return t.Risk.Score
+}
+
+// liquidityUSD returns the liquidity of the main pair, or zero.
+func liquidityUSD(t *token.TokenInfo) float64 {
+	if t.Liquidity == nil {
+		return 0
+	}
+	return t.Liquidity.LiquidityUSD
+}
+
+// pairAge returns the age of the main pair in hours, or zero.
+func pairAge(t *token.TokenInfo) float64 {
+	if t.Liquidity == nil {
+		return 0
+	}
+	return t.Liquidity.AgeHours
+}
+
+// holderCount returns the holder count reported by GoPlus, falling back to
+// the holders simulated by honeypot.is.
+func holderCount(t *token.TokenInfo) int {
+	if t.Concentration != nil && t.Concentration.HolderCount > 0 {
+		return t.Concentration.HolderCount
+	}
+	if t.HolderAnalysis != nil {
+		return t.HolderAnalysis.Holders
+	}
+	return 0
+}
+
+// yesNo formats a boolean.
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
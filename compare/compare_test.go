@@ -0,0 +1,111 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/token"
+)
+
+// scanned returns a complete scan with the given verdict.
+func scanned(level string, score int) *multiscan.Result {
+	return &multiscan.Result{Unified: &token.TokenInfo{Risk: &token.RiskVerdict{Level: level, Score: score}}}
+}
+
+// ranks returns the rank of every candidate by address.
+func ranks(comparison *Comparison) map[string]int {
+	got := map[string]int{}
+	for _, candidate := range comparison.Candidates {
+		got[candidate.Address] = candidate.Rank
+	}
+	return got
+}
+
+func checkRanks(t *testing.T, comparison *Comparison, want map[string]int) {
+	t.Helper()
+	got := ranks(comparison)
+	for address, rank := range want {
+		if got[address] != rank {
+			t.Errorf("%s: got rank %d, want %d (ranks %v)", address, got[address], rank, got)
+		}
+	}
+}
+
+func TestCompareRanksUnassessedLast(t *testing.T) {
+	failed := scanned(token.VerdictSafe, 5)
+	failed.Raw = &multiscan.RawResponses{Errors: map[string]string{multiscan.ProviderGoPlus: "timeout"}}
+	partial := scanned(token.VerdictCaution, 20)
+	partial.Unified.Risk.Incomplete = true
+	noData := scanned(token.VerdictUnknown, 0)
+	noData.Unified.Risk.Incomplete = true
+
+	addresses := []string{"0xnodata", "0xunrated", "0xfailed", "0xpartial", "0xdanger", "0xsafe"}
+	results := []*multiscan.Result{noData, {Unified: &token.TokenInfo{}}, failed, partial, scanned(token.VerdictDanger, 60), scanned(token.VerdictSafe, 10)}
+	comparison, err := Compare(addresses, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Complete scans first, then incomplete ones with a verdict, then the rest
+	checkRanks(t, comparison, map[string]int{"0xsafe": 1, "0xdanger": 2, "0xfailed": 3, "0xpartial": 4, "0xnodata": 5, "0xunrated": 6})
+	if comparison.MostLikelyLegitimate != "0xsafe" {
+		t.Errorf("got most likely legitimate %q, want 0xsafe", comparison.MostLikelyLegitimate)
+	}
+	for _, candidate := range comparison.Candidates {
+		want := candidate.Address != "0xsafe" && candidate.Address != "0xdanger"
+		if candidate.Incomplete != want {
+			t.Errorf("%s: got incomplete %v, want %v", candidate.Address, candidate.Incomplete, want)
+		}
+	}
+}
+
+func TestCompareWithoutCompleteScans(t *testing.T) {
+	noData := scanned(token.VerdictUnknown, 0)
+	noData.Unified.Risk.Incomplete = true
+	partial := scanned(token.VerdictSafe, 5)
+	partial.Unified.Risk.Incomplete = true
+
+	comparison, err := Compare([]string{"0xnodata", "0xpartial"}, []*multiscan.Result{noData, partial})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRanks(t, comparison, map[string]int{"0xpartial": 1, "0xnodata": 2})
+	if comparison.MostLikelyLegitimate != "" {
+		t.Errorf("got most likely legitimate %q, want none", comparison.MostLikelyLegitimate)
+	}
+}
+
+func TestCompareTies(t *testing.T) {
+	withData := func(liquidity float64, holders int, ageHours float64) *multiscan.Result {
+		result := scanned(token.VerdictSafe, 10)
+		result.Unified.Liquidity = &token.Liquidity{LiquidityUSD: liquidity, AgeHours: ageHours}
+		result.Unified.Concentration = &token.Concentration{HolderCount: holders}
+		return result
+	}
+	tests := []struct {
+		name    string
+		results []*multiscan.Result
+		want    map[string]int
+	}{
+		{"lower score", []*multiscan.Result{scanned(token.VerdictCaution, 30), scanned(token.VerdictSafe, 10)}, map[string]int{"0xa": 2, "0xb": 1}},
+		{"deeper liquidity", []*multiscan.Result{withData(1000, 900, 900), withData(50000, 10, 1)}, map[string]int{"0xa": 2, "0xb": 1}},
+		{"more holders", []*multiscan.Result{withData(1000, 10, 900), withData(1000, 500, 1)}, map[string]int{"0xa": 2, "0xb": 1}},
+		{"older pair", []*multiscan.Result{withData(1000, 10, 24), withData(1000, 10, 2400)}, map[string]int{"0xa": 2, "0xb": 1}},
+		{"full tie keeps the given order", []*multiscan.Result{withData(1000, 10, 24), withData(1000, 10, 24)}, map[string]int{"0xa": 1, "0xb": 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comparison, err := Compare([]string{"0xa", "0xb"}, tt.results)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkRanks(t, comparison, tt.want)
+		})
+	}
+}
+
+func TestCompareMismatchedResults(t *testing.T) {
+	if _, err := Compare([]string{"0xa", "0xb"}, []*multiscan.Result{scanned(token.VerdictSafe, 0)}); err == nil {
+		t.Error("got no error for fewer results than addresses")
+	}
+}
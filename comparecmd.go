@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/s-Amine/token-scan/compare"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+)
+
+// compareCommand implements the compare command. It scans several tokens and
+// prints them side by side, ranked by how likely each one is legitimate.
+func compareCommand(flags *flag.FlagSet) func(args []string) {
+	tokens := flags.String("tokens", "", "Comma-separated token hashes to compare")
+	noHistory := flags.Bool("no-history", false, "Do not record the scans in the history store")
+
+	return func(args []string) {
+		addresses, _ := tokenList(*tokens, "")
+		addresses = append(addresses, args...)
+		if len(addresses) < 2 {
+			usageError(flags, "at least two token hashes are required")
+		}
+		c := globals.scanChain
+		cfg := loadConfig()
+
+		// Scan the tokens concurrently, keeping the results in address order
+		results := make([]*multiscan.Result, len(addresses))
+		var wg sync.WaitGroup
+		for i, address := range addresses {
+			wg.Add(1)
+			go func(i int, address string) {
+				defer wg.Done()
+				results[i] = multiscan.ScanWithOptions(address, multiscan.Options{Chain: c, IncludeRaw: true})
+			}(i, address)
+		}
+		wg.Wait()
+
+		if !*noHistory && !cfg.History.Disabled {
+			for i, address := range addresses {
+				recordHistory(cfg.History.Path, "multiscan", c, address, results[i])
+			}
+		}
+
+		comparison, err := compare.Compare(addresses, results)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		printResult(comparison)
+	}
+}
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/output"
+	"github.com/s-Amine/token-scan/token"
+)
+
+// completionShells lists the shells completion scripts are generated for.
+var completionShells = []string{"bash", "zsh", "fish"}
+
+// fileFlags are the flags whose value is a file name.
+var fileFlags = map[string]bool{"config": true, "file": true, "watchlist": true, "out": true}
+
+// dirFlags are the flags whose value is a directory name.
+var dirFlags = map[string]bool{"record": true, "replay": true}
+
+// flagWords returns the values completed for a flag, by flag name.
+func flagWords(name string) []string {
+	switch name {
+	case "chain":
+		var names []string
+		for _, c := range chain.All {
+			names = append(names, c.Name)
+		}
+		return names
+	case "output":
+		return output.Formats
+	case "mode":
+		return scanModes
+	case "min-severity":
+		return []string{string(token.SeverityLow), string(token.SeverityMedium), string(token.SeverityHigh), string(token.SeverityCritical)}
+	case "verdict":
+		return []string{token.VerdictSafe, token.VerdictCaution, token.VerdictDanger, token.VerdictUnknown}
+	}
+	return nil
+}
+
+// shellFlag is a flag as described to shells.
+type shellFlag struct {
+	name       string
+	usage      string
+	takesValue bool
+}
+
+// shellCommand is a command as described to shells.
+type shellCommand struct {
+	command
+	flags []shellFlag
+}
+
+// completionCommand implements the completion command. It prints a bash,
+// zsh or fish completion script covering every command and flag.
+func completionCommand(flags *flag.FlagSet) func(args []string) {
+	return func(args []string) {
+		if len(args) != 1 {
+			usageError(flags, "one shell is required: %s", strings.Join(completionShells, ", "))
+		}
+
+		var cmds []shellCommand
+		for _, cmd := range commands() {
+			cmds = append(cmds, shellCommand{cmd, describeFlags(cmd.setup)})
+		}
+		globalSpecs := describeFlags(func(flags *flag.FlagSet) func([]string) {
+			defaults := globalOptions{}
+			defaults.register(flags)
+			return nil
+		})
+
+		switch args[0] {
+		case "bash":
+			writeBashCompletion(os.Stdout, cmds, globalSpecs)
+		case "zsh":
+			writeZshCompletion(os.Stdout, cmds, globalSpecs)
+		case "fish":
+			writeFishCompletion(os.Stdout, cmds, globalSpecs)
+		default:
+			usageError(flags, "unknown shell %q", args[0])
+		}
+	}
+}
+
+// describeFlags lists the flags registered by setup.
+func describeFlags(setup func(flags *flag.FlagSet) func([]string)) []shellFlag {
+	flags := flag.NewFlagSet("completion", flag.ContinueOnError)
+	setup(flags)
+
+	var described []shellFlag
+	flags.VisitAll(func(f *flag.Flag) {
+		boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+		described = append(described, shellFlag{
+			name:       f.Name,
+			usage:      f.Usage,
+			takesValue: !ok || !boolFlag.IsBoolFlag(),
+		})
+	})
+	return described
+}
+
+// commandNames lists the names of cmds and the help command.
+func commandNames(cmds []shellCommand) []string {
+	var names []string
+	for _, cmd := range cmds {
+		names = append(names, cmd.name)
+	}
+	return append(names, "help")
+}
+
+// flagNames lists the names of flags prefixed with a dash.
+func flagNames(flags []shellFlag) []string {
+	var names []string
+	for _, f := range flags {
+		names = append(names, "-"+f.name)
+	}
+	return names
+}
+
+// writeBashCompletion writes the bash completion script.
+func writeBashCompletion(w io.Writer, cmds []shellCommand, globalFlags []shellFlag) {
+	// Flags taking a value, grouped by how the value is completed
+	valueFlags := map[string]bool{}
+	for _, f := range globalFlags {
+		if f.takesValue {
+			valueFlags[f.name] = true
+		}
+	}
+	for _, cmd := range cmds {
+		for _, f := range cmd.flags {
+			if f.takesValue {
+				valueFlags[f.name] = true
+			}
+		}
+	}
+	var names []string
+	for name := range valueFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprint(w, `# bash completion for token-scan. Load it with:
+#   source <(token-scan completion bash)
+
+_token_scan() {
+	local cur prev command words i
+	cur="${COMP_WORDS[COMP_CWORD]}"
+	prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+	# Find the command, skipping the global flags and their values
+	command=""
+	for ((i = 1; i < COMP_CWORD; i++)); do
+		case "${COMP_WORDS[i]}" in
+`)
+	var globalValues []string
+	for _, f := range globalFlags {
+		if f.takesValue {
+			globalValues = append(globalValues, "-"+f.name)
+		}
+	}
+	fmt.Fprintf(w, "\t\t%s) ((i++)) ;;\n", strings.Join(globalValues, "|"))
+	fmt.Fprint(w, `		-*) ;;
+		*) command="${COMP_WORDS[i]}"; break ;;
+		esac
+	done
+
+	case "$prev" in
+`)
+	for _, name := range names {
+		switch {
+		case flagWords(name) != nil:
+			fmt.Fprintf(w, "\t-%s) COMPREPLY=($(compgen -W %q -- \"$cur\")); return ;;\n", name, strings.Join(flagWords(name), " "))
+		case fileFlags[name]:
+			fmt.Fprintf(w, "\t-%s) COMPREPLY=($(compgen -f -- \"$cur\")); return ;;\n", name)
+		case dirFlags[name]:
+			fmt.Fprintf(w, "\t-%s) COMPREPLY=($(compgen -d -- \"$cur\")); return ;;\n", name)
+		default:
+			fmt.Fprintf(w, "\t-%s) return ;;\n", name)
+		}
+	}
+	fmt.Fprint(w, `	esac
+
+	case "$command" in
+`)
+	global := strings.Join(flagNames(globalFlags), " ")
+	fmt.Fprintf(w, "\t\"\") words=%q ;;\n", strings.Join(commandNames(cmds), " ")+" "+global)
+	fmt.Fprintf(w, "\thelp) words=%q ;;\n", strings.Join(commandNames(cmds), " "))
+	for _, cmd := range cmds {
+		words := append(append([]string{}, cmd.words...), flagNames(cmd.flags)...)
+		fmt.Fprintf(w, "\t%s) words=%q ;;\n", cmd.name, strings.TrimSpace(strings.Join(words, " ")+" "+global))
+	}
+	fmt.Fprint(w, `	esac
+	COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+
+complete -o default -F _token_scan token-scan
+`)
+}
+
+// zshQuote quotes s for a single-quoted zsh word inside an option
+// description.
+func zshQuote(s string) string {
+	s = strings.NewReplacer("[", `\[`, "]", `\]`, ":", `\:`).Replace(s)
+	return strings.ReplaceAll(s, "'", `'\''`)
+}
+
+// zshFlagSpecs returns the _arguments specs of flags.
+func zshFlagSpecs(flags []shellFlag) []string {
+	var specs []string
+	for _, f := range flags {
+		spec := fmt.Sprintf("'-%s[%s]", f.name, zshQuote(f.usage))
+		switch {
+		case !f.takesValue:
+		case flagWords(f.name) != nil:
+			spec += fmt.Sprintf(":%s:(%s)", f.name, strings.Join(flagWords(f.name), " "))
+		case fileFlags[f.name]:
+			spec += ":file:_files"
+		case dirFlags[f.name]:
+			spec += ":directory:_files -/"
+		default:
+			spec += fmt.Sprintf(":%s: ", f.name)
+		}
+		specs = append(specs, spec+"'")
+	}
+	return specs
+}
+
+// writeZshCompletion writes the zsh completion script.
+func writeZshCompletion(w io.Writer, cmds []shellCommand, globalFlags []shellFlag) {
+	fmt.Fprint(w, `#compdef token-scan
+# zsh completion for token-scan. Load it with:
+#   source <(token-scan completion zsh)
+# or save it as _token-scan in a directory of $fpath.
+
+_token-scan() {
+	local curcontext="$curcontext" state line
+	local -a global_flags commands
+	global_flags=(
+`)
+	for _, spec := range zshFlagSpecs(globalFlags) {
+		fmt.Fprintf(w, "\t\t%s\n", spec)
+	}
+	fmt.Fprint(w, "\t)\n\tcommands=(\n")
+	for _, cmd := range cmds {
+		fmt.Fprintf(w, "\t\t'%s:%s'\n", cmd.name, zshQuote(cmd.summary))
+	}
+	fmt.Fprint(w, `		'help:Show the help of a command'
+	)
+
+	_arguments -C $global_flags '1:command:->command' '*::argument:->argument'
+	case $state in
+	command)
+		_describe -t commands command commands
+		;;
+	argument)
+		case $words[1] in
+`)
+	fmt.Fprintf(w, "\t\thelp)\n\t\t\t_arguments '1:command:(%s)'\n\t\t\t;;\n", strings.Join(commandNames(cmds)[:len(cmds)], " "))
+	for _, cmd := range cmds {
+		specs := zshFlagSpecs(cmd.flags)
+		switch {
+		case len(cmd.words) > 0:
+			specs = append(specs, fmt.Sprintf("'1:%s:(%s)'", cmd.name, strings.Join(cmd.words, " ")), "'*:argument: '")
+		case cmd.files:
+			specs = append(specs, "'*:scan:_files'")
+		case cmd.args != "":
+			specs = append(specs, "'*:argument: '")
+		}
+		fmt.Fprintf(w, "\t\t%s)\n\t\t\t_arguments $global_flags", cmd.name)
+		for _, spec := range specs {
+			fmt.Fprintf(w, " \\\n\t\t\t\t%s", spec)
+		}
+		fmt.Fprint(w, "\n\t\t\t;;\n")
+	}
+	fmt.Fprint(w, `		esac
+		;;
+	esac
+}
+
+if [ "$funcstack[1]" = "_token-scan" ]; then
+	_token-scan "$@"
+else
+	compdef _token-scan token-scan
+fi
+`)
+}
+
+// fishQuote quotes s as a single-quoted fish word.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// fishFlagLine returns the complete command of a flag under condition.
+func fishFlagLine(condition string, f shellFlag) string {
+	line := "complete -c token-scan"
+	if condition != "" {
+		line += " -n " + fishQuote(condition)
+	}
+	// Single letter flags are short options to fish
+	option := "-o"
+	if len(f.name) == 1 {
+		option = "-s"
+	}
+	line += fmt.Sprintf(" %s %s -d %s", option, f.name, fishQuote(f.usage))
+	switch {
+	case !f.takesValue:
+	case flagWords(f.name) != nil:
+		line += " -xa " + fishQuote(strings.Join(flagWords(f.name), " "))
+	case fileFlags[f.name], dirFlags[f.name]:
+		line += " -rF"
+	default:
+		line += " -x"
+	}
+	return line
+}
+
+// writeFishCompletion writes the fish completion script.
+func writeFishCompletion(w io.Writer, cmds []shellCommand, globalFlags []shellFlag) {
+	fmt.Fprint(w, `# fish completion for token-scan. Load it with:
+#   token-scan completion fish | source
+# or save it as ~/.config/fish/completions/token-scan.fish.
+
+complete -c token-scan -f
+`)
+	for _, cmd := range cmds {
+		fmt.Fprintf(w, "complete -c token-scan -n __fish_use_subcommand -a %s -d %s\n", cmd.name, fishQuote(cmd.summary))
+	}
+	fmt.Fprintln(w, "complete -c token-scan -n __fish_use_subcommand -a help -d 'Show the help of a command'")
+	fmt.Fprintf(w, "complete -c token-scan -n '__fish_seen_subcommand_from help' -a %s\n", fishQuote(strings.Join(commandNames(cmds)[:len(cmds)], " ")))
+	for _, f := range globalFlags {
+		fmt.Fprintln(w, fishFlagLine("", f))
+	}
+	for _, cmd := range cmds {
+		condition := "__fish_seen_subcommand_from " + cmd.name
+		if len(cmd.words) > 0 {
+			fmt.Fprintf(w, "complete -c token-scan -n %s -a %s\n", fishQuote(condition), fishQuote(strings.Join(cmd.words, " ")))
+		}
+		if cmd.files {
+			fmt.Fprintf(w, "complete -c token-scan -n %s -F\n", fishQuote(condition))
+		}
+		for _, f := range cmd.flags {
+			fmt.Fprintln(w, fishFlagLine(condition, f))
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/s-Amine/token-scan/alert"
+	"github.com/s-Amine/token-scan/history"
+	"github.com/s-Amine/token-scan/scanners/goplus"
+	"github.com/s-Amine/token-scan/scanners/quickintel"
+	"github.com/s-Amine/token-scan/telegram"
+	"github.com/s-Amine/token-scan/token"
+)
+
+// Environment variables read by Load.
+const (
+	EnvConfigPath      = "TOKENSCAN_CONFIG"
+	EnvGoPlusAppKey    = "TOKENSCAN_GOPLUS_APP_KEY"
+	EnvGoPlusAppSecret = "TOKENSCAN_GOPLUS_APP_SECRET"
+	EnvQuickIntelKey   = "TOKENSCAN_QUICKINTEL_API_KEY"
+	EnvQuickIntelTier  = "TOKENSCAN_QUICKINTEL_TIER"
+	EnvTelegramToken   = "TOKENSCAN_TELEGRAM_TOKEN"
+)
+
+// Config represents the token-scan configuration file.
+type Config struct {
+	GoPlus     goplus.Config     `json:"goplus"`
+	QuickIntel quickintel.Config `json:"quickintel"`
+	Thresholds token.Thresholds  `json:"thresholds"`
+	History    history.Config    `json:"history"`
+	Alerts     alert.Config      `json:"alerts"`
+	Telegram   telegram.Config   `json:"telegram"`
+}
+
+// Load reads the JSON configuration file at path and applies environment
+// overrides on top of it. When path is empty the TOKENSCAN_CONFIG variable is
+// used; when that is empty too only the environment is consulted.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+
+	if path = ResolvePath(path); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading config file: %v", err)
+		}
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("error parsing config file %s: %v", path, err)
+		}
+	}
+
+	cfg.applyEnv()
+
+	return cfg, nil
+}
+
+// ResolvePath returns the configuration file Load reads for path: path
+// itself, or the TOKENSCAN_CONFIG variable when path is empty.
+func ResolvePath(path string) string {
+	if path == "" {
+		return os.Getenv(EnvConfigPath)
+	}
+	return path
+}
+
+// applyEnv overrides configuration values with any environment variables set.
+func (c *Config) applyEnv() {
+	setFromEnv(EnvGoPlusAppKey, &c.GoPlus.AppKey)
+	setFromEnv(EnvGoPlusAppSecret, &c.GoPlus.AppSecret)
+	setFromEnv(EnvQuickIntelKey, &c.QuickIntel.APIKey)
+	setFromEnv(EnvQuickIntelTier, &c.QuickIntel.Tier)
+	setFromEnv(history.EnvHistoryPath, &c.History.Path)
+	setFromEnv(EnvTelegramToken, &c.Telegram.Token)
+}
+
+// Apply configures the scanner packages with this configuration.
+func (c *Config) Apply() {
+	goplus.Configure(c.GoPlus)
+	quickintel.Configure(c.QuickIntel)
+	token.SetThresholds(c.Thresholds)
+}
+
+// redactedValue replaces credentials in Redacted.
+const redactedValue = "REDACTED"
+
+// Redacted returns a copy of the configuration fit for display, with
+// credentials replaced and sink URLs reduced to their scheme and host.
+func (c *Config) Redacted() *Config {
+	r := *c
+	redact(&r.GoPlus.AppSecret)
+	redact(&r.QuickIntel.APIKey)
+	redact(&r.Telegram.Token)
+	r.Alerts.Sinks = append([]alert.SinkConfig(nil), c.Alerts.Sinks...)
+	for i := range r.Alerts.Sinks {
+		sink := &r.Alerts.Sinks[i]
+		redact(&sink.Secret)
+		redact(&sink.Password)
+		// Slack webhook URLs embed their credentials in the path
+		if u, err := url.Parse(sink.URL); err == nil && u.Host != "" && (u.Path != "" || u.RawQuery != "") {
+			sink.URL = u.Scheme + "://" + u.Host + "/" + redactedValue
+		}
+	}
+	return &r
+}
+
+// redact replaces a credential that is set.
+func redact(field *string) {
+	if *field != "" {
+		*field = redactedValue
+	}
+}
+
+// setFromEnv sets field to the value of the environment variable when it is set.
+func setFromEnv(name string, field *string) {
+	if value, ok := os.LookupEnv(name); ok {
+		*field = value
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/s-Amine/token-scan/config"
+)
+
+// configCommand implements the config show and path commands.
+func configCommand(flags *flag.FlagSet) func(args []string) {
+	return func(args []string) {
+		if len(args) != 1 {
+			usageError(flags, "config show or config path is required")
+		}
+
+		switch args[0] {
+		case "show":
+			// Credentials are masked; environment overrides are included
+			cfg := loadConfig()
+			printResult(cfg.Redacted())
+		case "path":
+			path := config.ResolvePath(globals.config)
+			if path == "" {
+				fmt.Println("No configuration file; set -config or $" + config.EnvConfigPath)
+				return
+			}
+			fmt.Println(path)
+		default:
+			usageError(flags, "unknown config command %q", args[0])
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package decode
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxProblemValue is the length at which problem values are truncated.
+const maxProblemValue = 80
+
+// Problem describes a field whose value could not be decoded. The field is
+// left at its zero value.
+type Problem struct {
+	// Field is the JSON path of the field, such as tokenDetails.tokenSupply.
+	Field  string `json:"field"`
+	Value  string `json:"value"`
+	Reason string `json:"reason"`
+}
+
+// String returns a human readable description of the problem.
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s (value %s)", p.Field, p.Reason, p.Value)
+}
+
+// Report describes how a JSON document matched the destination type.
+// Field paths in UnknownFields and MissingFields use [] for array elements
+// and * for map values so that they can be aggregated across documents.
+type Report struct {
+	Problems []Problem `json:"problems,omitempty"`
+	// UnknownFields are present in the document but not in the destination type.
+	UnknownFields []string `json:"unknown_fields,omitempty"`
+	// MissingFields are declared by the destination type but absent from the document.
+	MissingFields []string `json:"missing_fields,omitempty"`
+}
+
+// Decode unmarshals the JSON document data into v, which must be a non-nil
+// pointer. Unlike json.Unmarshal it does not stop at the first field of the
+// wrong type: every such field is left at its zero value and reported as a
+// Problem. An error is only returned when data is not valid JSON.
+func Decode(data []byte, v interface{}) ([]Problem, error) {
+	report, err := DecodeReport(data, v)
+	if err != nil {
+		return nil, err
+	}
+	return report.Problems, nil
+}
+
+// DecodeReport decodes data into v like Decode and also reports the fields
+// that are unknown to or missing from the destination type.
+func DecodeReport(data []byte, v interface{}) (*Report, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, fmt.Errorf("decode: non-nil pointer required, got %T", v)
+	}
+
+	var tree interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	d := &decoder{unknown: make(map[string]bool), missing: make(map[string]bool)}
+	d.value(rv.Elem(), tree, "", "")
+	d.report.UnknownFields = sortedKeys(d.unknown)
+	d.report.MissingFields = sortedKeys(d.missing)
+	return &d.report, nil
+}
+
+// decoder walks a generic JSON tree alongside the destination value.
+type decoder struct {
+	report  Report
+	unknown map[string]bool
+	missing map[string]bool
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// value decodes node into rv, which must be settable. path is the exact JSON
+// path of node and schema its normalized form.
+func (d *decoder) value(rv reflect.Value, node interface{}, path, schema string) {
+	if node == nil {
+		return
+	}
+
+	if rv.Kind() != reflect.Ptr && rv.CanAddr() && rv.Addr().Type().Implements(unmarshalerType) {
+		raw, _ := json.Marshal(node)
+		if err := rv.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(raw); err != nil {
+			d.fail(path, node, err.Error())
+		}
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		target := reflect.New(rv.Type().Elem())
+		d.value(target.Elem(), node, path, schema)
+		rv.Set(target)
+	case reflect.Struct:
+		object, ok := node.(map[string]interface{})
+		if !ok {
+			d.fail(path, node, "expected an object")
+			return
+		}
+		d.object(rv, object, path, schema)
+	case reflect.Slice:
+		array, ok := node.([]interface{})
+		if !ok {
+			d.fail(path, node, "expected an array")
+			return
+		}
+		slice := reflect.MakeSlice(rv.Type(), len(array), len(array))
+		for i, item := range array {
+			d.value(slice.Index(i), item, path+"["+strconv.Itoa(i)+"]", schema+"[]")
+		}
+		rv.Set(slice)
+	case reflect.Map:
+		object, ok := node.(map[string]interface{})
+		if !ok || rv.Type().Key().Kind() != reflect.String {
+			d.fail(path, node, "expected an object")
+			return
+		}
+		m := reflect.MakeMapWithSize(rv.Type(), len(object))
+		for key, item := range object {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			d.value(elem, item, joinPath(path, key), joinPath(schema, "*"))
+			m.SetMapIndex(reflect.ValueOf(key).Convert(rv.Type().Key()), elem)
+		}
+		rv.Set(m)
+	default:
+		raw, _ := json.Marshal(node)
+		if err := json.Unmarshal(raw, rv.Addr().Interface()); err != nil {
+			d.fail(path, node, fmt.Sprintf("expected %s", rv.Type()))
+		}
+	}
+}
+
+// object decodes the fields of a JSON object into the struct rv.
+func (d *decoder) object(rv reflect.Value, object map[string]interface{}, path, schema string) {
+	matched := make(map[string]bool, len(object))
+	for _, field := range structFields(rv.Type()) {
+		key, node, ok := lookup(object, field.name)
+		if !ok {
+			d.missing[joinPath(schema, field.name)] = true
+			continue
+		}
+		matched[key] = true
+		d.value(rv.FieldByIndex(field.index), node, joinPath(path, field.name), joinPath(schema, field.name))
+	}
+	for key := range object {
+		if !matched[key] {
+			d.unknown[joinPath(schema, key)] = true
+		}
+	}
+}
+
+// fail records a problem for the field at path.
+func (d *decoder) fail(path string, node interface{}, reason string) {
+	raw, _ := json.Marshal(node)
+	value := string(raw)
+	if len(value) > maxProblemValue {
+		value = value[:maxProblemValue] + "..."
+	}
+	d.report.Problems = append(d.report.Problems, Problem{Field: path, Value: value, Reason: reason})
+}
+
+// field is a struct field decoded from the JSON key name.
+type field struct {
+	name  string
+	index []int
+}
+
+// structFields returns the JSON fields of struct type t, flattening embedded
+// structs without a JSON name the way encoding/json does.
+func structFields(t reflect.Type) []field {
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" || f.Tag.Get("decode") == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for _, embedded := range structFields(f.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				fields = append(fields, embedded)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, field{name: name, index: []int{i}})
+	}
+	return fields
+}
+
+// lookup finds key in object, falling back to a case-insensitive match as
+// encoding/json does. It returns the key as spelled in the object.
+func lookup(object map[string]interface{}, key string) (string, interface{}, bool) {
+	if node, ok := object[key]; ok {
+		return key, node, true
+	}
+	for k, node := range object {
+		if strings.EqualFold(k, key) {
+			return k, node, true
+		}
+	}
+	return "", nil, false
+}
+
+// sortedKeys returns the keys of set in alphabetical order.
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinPath appends key to the JSON path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,221 @@
+package decode
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// scalars holds one field of every kind the decoder handles.
+type scalars struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	Flag  bool   `json:"flag"`
+	Int   Int    `json:"int"`
+	Float Float  `json:"float"`
+	Bool  Bool   `json:"bool"`
+	Str   String `json:"str"`
+	Big   BigInt `json:"big"`
+	Ptr   *Float `json:"ptr"`
+}
+
+// bigInt parses a decimal integer for expectations.
+func bigInt(text string) BigInt {
+	n, _ := new(big.Int).SetString(text, 10)
+	return NewBigInt(n)
+}
+
+func TestDecodeTypes(t *testing.T) {
+	half := Float(0.5)
+	tests := []struct {
+		name     string
+		data     string
+		want     scalars
+		problems []string
+	}{
+		{"matching types", `{"name":"Pepe","count":3,"flag":true}`, scalars{Name: "Pepe", Count: 3, Flag: true}, nil},
+		{"number for string", `{"name":5,"count":3}`, scalars{Count: 3}, []string{"name"}},
+		{"string for int", `{"count":"7"}`, scalars{}, []string{"count"}},
+		{"string for bool", `{"flag":"true","name":"kept"}`, scalars{Name: "kept"}, []string{"flag"}},
+		{"object for string", `{"name":{"first":"P"}}`, scalars{}, []string{"name"}},
+		{"numeric string Int", `{"int":"42"}`, scalars{Int: 42}, nil},
+		{"integral float Int", `{"int":1e3}`, scalars{Int: 1000}, nil},
+		{"fractional Int", `{"int":"4.5"}`, scalars{}, []string{"int"}},
+		{"percent Float", `{"float":"12.5%"}`, scalars{Float: 12.5}, nil},
+		{"text Float", `{"float":"high"}`, scalars{}, []string{"float"}},
+		{"number Bool", `{"bool":1}`, scalars{Bool: true}, nil},
+		{"string Bool", `{"bool":"false"}`, scalars{}, nil},
+		{"word Bool", `{"bool":"yes"}`, scalars{}, []string{"bool"}},
+		{"number String", `{"str":12}`, scalars{Str: "12"}, nil},
+		{"boolean String", `{"str":true}`, scalars{Str: "true"}, nil},
+		{"array String", `{"str":[1]}`, scalars{}, []string{"str"}},
+		{"exponent BigInt", `{"big":1e27}`, scalars{Big: bigInt("1000000000000000000000000000")}, nil},
+		{"string BigInt", `{"big":"123456789012345678901234567890"}`, scalars{Big: bigInt("123456789012345678901234567890")}, nil},
+		{"text BigInt", `{"big":"lots"}`, scalars{}, []string{"big"}},
+		{"nulls", `{"name":null,"int":null,"big":null,"ptr":null}`, scalars{}, nil},
+		{"empty strings", `{"int":"","float":"","bool":""}`, scalars{}, nil},
+		{"pointer", `{"ptr":"0.5"}`, scalars{Ptr: &half}, nil},
+		{"case-insensitive keys", `{"NAME":"Pepe","Count":1}`, scalars{Name: "Pepe", Count: 1}, nil},
+	}
+	for _, tt := range tests {
+		var got scalars
+		problems, err := Decode([]byte(tt.data), &got)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+		var fields []string
+		for _, problem := range problems {
+			fields = append(fields, problem.Field)
+		}
+		if !reflect.DeepEqual(fields, tt.problems) {
+			t.Errorf("%s: got problems %v, want problems at %v", tt.name, problems, tt.problems)
+		}
+	}
+}
+
+// nested exercises nested structs, slices, maps and embedded structs.
+type nested struct {
+	embedded
+	Token struct {
+		Name    string `json:"name"`
+		Details struct {
+			Supply BigInt `json:"supply"`
+		} `json:"details"`
+	} `json:"token"`
+	Holders []struct {
+		Address string `json:"address"`
+		Share   Float  `json:"share"`
+	} `json:"holders"`
+	Scores  map[string]Int `json:"scores"`
+	Skipped string         `json:"skipped" decode:"-"`
+	Ignored string         `json:"-"`
+}
+
+type embedded struct {
+	ID Int `json:"id"`
+}
+
+func TestDecodeReportNested(t *testing.T) {
+	data := `{
+		"id": "9",
+		"token": {"name": "Pepe", "details": {"supply": "1000", "burned": 1}},
+		"holders": [
+			{"address": "0x1", "share": "0.5"},
+			{"address": "0x2", "share": "half", "tag": "team"}
+		],
+		"scores": {"goplus": 1, "honeypot": "x"},
+		"skipped": "present",
+		"extra": true
+	}`
+	var got nested
+	report, err := DecodeReport([]byte(data), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.ID != 9 || got.Token.Name != "Pepe" || got.Token.Details.Supply.String() != "1000" {
+		t.Errorf("got id %d, name %q, supply %s", got.ID, got.Token.Name, got.Token.Details.Supply)
+	}
+	if len(got.Holders) != 2 || got.Holders[0].Share != 0.5 || got.Holders[1].Address != "0x2" || got.Holders[1].Share != 0 {
+		t.Errorf("got holders %+v", got.Holders)
+	}
+	if got.Scores["goplus"] != 1 || got.Scores["honeypot"] != 0 {
+		t.Errorf("got scores %v", got.Scores)
+	}
+	if got.Skipped != "" {
+		t.Errorf("got skipped field %q, want it left alone", got.Skipped)
+	}
+
+	var fields []string
+	for _, problem := range report.Problems {
+		fields = append(fields, problem.Field)
+	}
+	// Problem paths are exact; drift paths are normalized across elements
+	wantProblems := map[string]bool{"holders[1].share": true, "scores.honeypot": true}
+	if len(fields) != len(wantProblems) {
+		t.Errorf("got problems %v, want problems at %v", report.Problems, wantProblems)
+	}
+	for _, field := range fields {
+		if !wantProblems[field] {
+			t.Errorf("unexpected problem at %s", field)
+		}
+	}
+	if want := []string{"extra", "holders[].tag", "skipped", "token.details.burned"}; !reflect.DeepEqual(report.UnknownFields, want) {
+		t.Errorf("got unknown fields %v, want %v", report.UnknownFields, want)
+	}
+	if report.MissingFields != nil {
+		t.Errorf("got missing fields %v, want none", report.MissingFields)
+	}
+}
+
+func TestDecodeReportMissing(t *testing.T) {
+	var got nested
+	report, err := DecodeReport([]byte(`{"token":{"details":{}},"holders":[{"address":"0x1"},{}]}`), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"holders[].address", "holders[].share", "id", "scores", "token.details.supply", "token.name"}
+	if !reflect.DeepEqual(report.MissingFields, want) {
+		t.Errorf("got missing fields %v, want %v", report.MissingFields, want)
+	}
+	if report.UnknownFields != nil || report.Problems != nil {
+		t.Errorf("got unknown fields %v and problems %v, want none", report.UnknownFields, report.Problems)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var got scalars
+	if _, err := Decode([]byte(`{"name":`), &got); err == nil {
+		t.Error("invalid JSON: got no error")
+	}
+	if _, err := Decode([]byte(`{}`), got); err == nil {
+		t.Error("non-pointer destination: got no error")
+	}
+	if _, err := Decode([]byte(`[1]`), &got); err != nil {
+		t.Errorf("array for struct: got error %v, want a problem", err)
+	}
+	problems, _ := Decode([]byte(`"text"`), &got)
+	if len(problems) != 1 || problems[0].Field != "" || problems[0].Reason != "expected an object" {
+		t.Errorf("string for struct: got problems %v", problems)
+	}
+}
+
+func TestRecordDrift(t *testing.T) {
+	wasEnabled := DriftTrackingEnabled()
+	t.Cleanup(func() {
+		EnableDriftTracking(wasEnabled)
+		ResetDrift()
+	})
+	ResetDrift()
+
+	EnableDriftTracking(false)
+	RecordDrift("quickintel", &Report{UnknownFields: []string{"ignored"}})
+	if summary := DriftSummary(); len(summary) != 0 {
+		t.Errorf("tracking disabled: got %v, want nothing recorded", summary)
+	}
+
+	EnableDriftTracking(true)
+	RecordDrift("quickintel", &Report{UnknownFields: []string{"a", "b"}, MissingFields: []string{"c"}})
+	RecordDrift("quickintel", &Report{UnknownFields: []string{"a"}})
+	RecordDrift("goplus", &Report{MissingFields: []string{"c"}})
+	RecordDrift("goplus", nil)
+
+	summary := DriftSummary()
+	quickIntel := summary["quickintel"]
+	if quickIntel.Responses != 2 || quickIntel.UnknownFields["a"] != 2 || quickIntel.UnknownFields["b"] != 1 || quickIntel.MissingFields["c"] != 1 {
+		t.Errorf("got quickintel drift %+v", quickIntel)
+	}
+	if goPlus := summary["goplus"]; goPlus.Responses != 1 || goPlus.MissingFields["c"] != 1 {
+		t.Errorf("got goplus drift %+v", goPlus)
+	}
+
+	// The summary is a copy
+	quickIntel.UnknownFields["a"] = 100
+	if DriftSummary()["quickintel"].UnknownFields["a"] != 2 {
+		t.Error("changing the summary changed the statistics")
+	}
+}
@@ -0,0 +1,88 @@
+package decode
+
+import "sync"
+
+// DriftStats aggregates the schema drift of one provider over several responses.
+// The maps count, per field path, the responses in which the field was unknown
+// or missing.
+type DriftStats struct {
+	Responses     int            `json:"responses"`
+	UnknownFields map[string]int `json:"unknown_fields,omitempty"`
+	MissingFields map[string]int `json:"missing_fields,omitempty"`
+}
+
+var (
+	driftMu      sync.Mutex
+	driftEnabled bool
+	drift        = make(map[string]*DriftStats)
+)
+
+// EnableDriftTracking turns the aggregation of schema drift by RecordDrift on or off.
+func EnableDriftTracking(enabled bool) {
+	driftMu.Lock()
+	defer driftMu.Unlock()
+
+	driftEnabled = enabled
+}
+
+// DriftTrackingEnabled reports whether schema drift is being aggregated.
+func DriftTrackingEnabled() bool {
+	driftMu.Lock()
+	defer driftMu.Unlock()
+
+	return driftEnabled
+}
+
+// RecordDrift adds the unknown and missing fields of report to the statistics
+// of provider. It does nothing unless drift tracking is enabled.
+func RecordDrift(provider string, report *Report) {
+	driftMu.Lock()
+	defer driftMu.Unlock()
+
+	if !driftEnabled || report == nil {
+		return
+	}
+	stats, ok := drift[provider]
+	if !ok {
+		stats = &DriftStats{UnknownFields: make(map[string]int), MissingFields: make(map[string]int)}
+		drift[provider] = stats
+	}
+	stats.Responses++
+	for _, field := range report.UnknownFields {
+		stats.UnknownFields[field]++
+	}
+	for _, field := range report.MissingFields {
+		stats.MissingFields[field]++
+	}
+}
+
+// DriftSummary returns a copy of the statistics aggregated so far, by provider.
+func DriftSummary() map[string]DriftStats {
+	driftMu.Lock()
+	defer driftMu.Unlock()
+
+	summary := make(map[string]DriftStats, len(drift))
+	for provider, stats := range drift {
+		copied := DriftStats{
+			Responses:     stats.Responses,
+			UnknownFields: make(map[string]int, len(stats.UnknownFields)),
+			MissingFields: make(map[string]int, len(stats.MissingFields)),
+		}
+		for field, count := range stats.UnknownFields {
+			copied.UnknownFields[field] = count
+		}
+		for field, count := range stats.MissingFields {
+			copied.MissingFields[field] = count
+		}
+		summary[provider] = copied
+	}
+	return summary
+}
+
+// ResetDrift discards the statistics aggregated so far.
+func ResetDrift() {
+	driftMu.Lock()
+	defer driftMu.Unlock()
+
+	drift = make(map[string]*DriftStats)
+}
@@ -0,0 +1,170 @@
+package decode
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Int is an integer accepting JSON numbers and numeric strings.
+// Empty strings and null decode to zero.
+type Int int64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (i *Int) UnmarshalJSON(data []byte) error {
+	text, ok := scalarText(data)
+	if !ok {
+		return fmt.Errorf("expected a number or a numeric string, got %s", data)
+	}
+	if text == "" {
+		*i = 0
+		return nil
+	}
+	if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+		*i = Int(n)
+		return nil
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt64 {
+		return fmt.Errorf("%q is not an integer", text)
+	}
+	*i = Int(f)
+	return nil
+}
+
+// Float is a floating point number accepting JSON numbers and numeric strings.
+// Empty strings and null decode to zero.
+type Float float64
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (f *Float) UnmarshalJSON(data []byte) error {
+	text, ok := scalarText(data)
+	if !ok {
+		return fmt.Errorf("expected a number or a numeric string, got %s", data)
+	}
+	if text == "" {
+		*f = 0
+		return nil
+	}
+	value, err := strconv.ParseFloat(strings.TrimSuffix(text, "%"), 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", text)
+	}
+	*f = Float(value)
+	return nil
+}
+
+// Bool is a boolean accepting JSON booleans, 0/1 numbers and their string forms.
+// Empty strings and null decode to false.
+type Bool bool
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *Bool) UnmarshalJSON(data []byte) error {
+	text, ok := scalarText(data)
+	if !ok {
+		return fmt.Errorf("expected a boolean, got %s", data)
+	}
+	if text == "" {
+		*b = false
+		return nil
+	}
+	value, err := strconv.ParseBool(text)
+	if err != nil {
+		return fmt.Errorf("%q is not a boolean", text)
+	}
+	*b = Bool(value)
+	return nil
+}
+
+// String is a string also accepting JSON numbers and booleans, kept verbatim.
+// Null decodes to the empty string.
+type String string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *String) UnmarshalJSON(data []byte) error {
+	text, ok := scalarText(data)
+	if !ok {
+		return fmt.Errorf("expected a string, got %s", data)
+	}
+	*s = String(text)
+	return nil
+}
+
+// BigInt is an arbitrary precision integer accepting JSON numbers, including
+// exponent notation such as 1e27, and numeric strings. It marshals as a JSON
+// string so that no consumer loses precision.
+type BigInt struct {
+	value *big.Int
+}
+
+// NewBigInt returns a BigInt holding n.
+func NewBigInt(n *big.Int) BigInt {
+	return BigInt{value: n}
+}
+
+// Int returns the value, or nil when the field was empty.
+func (b BigInt) Int() *big.Int {
+	return b.value
+}
+
+// String returns the decimal representation, or an empty string when empty.
+func (b BigInt) String() string {
+	if b.value == nil {
+		return ""
+	}
+	return b.value.String()
+}
+
+// MarshalJSON implements json.Marshaler.
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	if b.value == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(b.value.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *BigInt) UnmarshalJSON(data []byte) error {
+	text, ok := scalarText(data)
+	if !ok {
+		return fmt.Errorf("expected a number or a numeric string, got %s", data)
+	}
+	if text == "" {
+		b.value = nil
+		return nil
+	}
+	if n, ok := new(big.Int).SetString(text, 10); ok {
+		b.value = n
+		return nil
+	}
+	f, _, err := big.ParseFloat(text, 10, 256, big.ToNearestEven)
+	if err != nil || !f.IsInt() {
+		return fmt.Errorf("%q is not an integer", text)
+	}
+	b.value, _ = f.Int(nil)
+	return nil
+}
+
+// scalarText returns the text of a JSON string, number or boolean. Null yields
+// an empty string. Objects and arrays are rejected.
+func scalarText(data []byte) (string, bool) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		return "", true
+	}
+	switch data[0] {
+	case '{', '[':
+		return "", false
+	case '"':
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return "", false
+		}
+		return strings.TrimSpace(text), true
+	}
+	return string(data), true
+}
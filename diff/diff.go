@@ -0,0 +1,312 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/s-Amine/token-scan/jsonfields"
+	"github.com/s-Amine/token-scan/token"
+)
+
+// Change represents a field that differs between two scans. Old or New is
+// nil when the field is absent from that scan.
+type Change struct {
+	Field    string         `json:"field"`
+	Old      interface{}    `json:"old"`
+	New      interface{}    `json:"new"`
+	Severity token.Severity `json:"severity"`
+}
+
+// Report lists the changes between two scans.
+type Report struct {
+	Changes []Change `json:"changes"`
+	// Raw lists, per provider, the changes of the raw provider responses.
+	Raw map[string][]Change `json:"raw,omitempty"`
+}
+
+// Severity returns the highest severity among the unified changes, or an
+// empty severity when nothing changed.
+func (r *Report) Severity() token.Severity {
+	var highest token.Severity
+	for _, change := range r.Changes {
+		if change.Severity.Rank() > highest.Rank() {
+			highest = change.Severity
+		}
+	}
+	return highest
+}
+
+// ignoredFields change between every two scans and are not reported.
+var ignoredFields = map[string]bool{
+	"liquidity.age_hours": true,
+}
+
+// severityRule rates a change of a field; an empty severity means low.
+type severityRule struct {
+	pattern string
+	rate    func(old, new interface{}) token.Severity
+}
+
+// severityRules rate the security-relevant changes of unified fields. Patterns
+// follow path.Match and are matched against the dotted field path.
+var severityRules = []severityRule{
+	{"is_honeypot", becameTrue(token.SeverityCritical)},
+	{"cannot_buy", becameTrue(token.SeverityCritical)},
+	{"cannot_sell_all", becameTrue(token.SeverityCritical)},
+	{"owner_change_balance", becameTrue(token.SeverityHigh)},
+	{"can_take_back_ownership", becameTrue(token.SeverityHigh)},
+	{"hidden_owner", becameTrue(token.SeverityHigh)},
+	{"is_mintable", becameTrue(token.SeverityHigh)},
+	{"transfer_pausable", becameTrue(token.SeverityHigh)},
+	{"is_blacklisted", becameTrue(token.SeverityHigh)},
+	{"personal_slippage_modifiable", becameTrue(token.SeverityHigh)},
+	{"is_whitelisted", becameTrue(token.SeverityMedium)},
+	{"can_whitelist", becameTrue(token.SeverityMedium)},
+	{"external_call", becameTrue(token.SeverityMedium)},
+	{"trading_cooldown", becameTrue(token.SeverityMedium)},
+	{"is_open_source", becameFalse(token.SeverityHigh)},
+	{"buy_tax", increased(token.SeverityHigh)},
+	{"sell_tax", increased(token.SeverityHigh)},
+	{"transfer_tax", increased(token.SeverityMedium)},
+	{"post_reenable_sell_tax", increased(token.SeverityHigh)},
+	{"lp_locked_share", decreased(token.SeverityMedium)},
+	{"holder_analysis.siphoned_detected", becameTrue(token.SeverityHigh)},
+	{"holder_analysis.failed_sell_share", increased(token.SeverityHigh)},
+	{"liquidity.pair_address", changed(token.SeverityMedium)},
+	{"liquidity.liquidity_usd", decreased(token.SeverityMedium)},
+	{"concentration.top_holders_share", increased(token.SeverityMedium)},
+	{"concentration.creator_share", increased(token.SeverityMedium)},
+	{"concentration.owner_share", increased(token.SeverityMedium)},
+	{"concentration.lp_locked_share", decreased(token.SeverityMedium)},
+	{"concentration.lp_burned_share", decreased(token.SeverityMedium)},
+	{"ownership.renounced", becameFalse(token.SeverityCritical)},
+	{"ownership.owner", changed(token.SeverityHigh)},
+	{"ownership.hidden_owner", becameTrue(token.SeverityHigh)},
+	{"upgradeability.is_proxy", becameTrue(token.SeverityHigh)},
+	{"upgradeability.implementation", changed(token.SeverityHigh)},
+	{"trading_restrictions.*.exercisable", becameTrue(token.SeverityHigh)},
+	{"scam_findings", grew(token.SeverityHigh)},
+	{"risk.level", worseVerdict(token.SeverityHigh)},
+	{"risk.score", increased(token.SeverityMedium)},
+}
+
+// Tokens reports the fields that differ between two unified scans, rated by
+// how much the change matters to the security of the token.
+func Tokens(old, new *token.TokenInfo) ([]Change, error) {
+	changes, err := compare(old, new)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without an earlier verdict, the risk assessment has nothing to get worse from
+	unassessed := old == nil || old.Risk == nil || old.Risk.Level == token.VerdictUnknown
+
+	kept := changes[:0]
+	for _, change := range changes {
+		if ignoredFields[change.Field] {
+			continue
+		}
+		change.Severity = rate(change)
+		if unassessed && strings.HasPrefix(change.Field, "risk.") {
+			change.Severity = token.SeverityLow
+		}
+		kept = append(kept, change)
+	}
+	return kept, nil
+}
+
+// JSON reports the fields that differ between two raw provider responses.
+// Raw changes are not rated and have low severity.
+func JSON(old, new json.RawMessage) ([]Change, error) {
+	changes, err := compare(old, new)
+	if err != nil {
+		return nil, err
+	}
+	for i := range changes {
+		changes[i].Severity = token.SeverityLow
+	}
+	return changes, nil
+}
+
+// Scans compares two scans. Unified information is compared when both scans
+// have it, and raw responses of the providers present in both scans.
+func Scans(old, new *Scan) (*Report, error) {
+	report := &Report{Changes: []Change{}}
+
+	if old.Info != nil && new.Info != nil {
+		changes, err := Tokens(old.Info, new.Info)
+		if err != nil {
+			return nil, err
+		}
+		report.Changes = changes
+	}
+
+	for provider, oldRaw := range old.Raw {
+		newRaw, ok := new.Raw[provider]
+		if !ok {
+			continue
+		}
+		changes, err := JSON(oldRaw, newRaw)
+		if err != nil {
+			return nil, fmt.Errorf("error comparing %s responses: %v", provider, err)
+		}
+		if len(changes) == 0 {
+			continue
+		}
+		if report.Raw == nil {
+			report.Raw = map[string][]Change{}
+		}
+		report.Raw[provider] = changes
+	}
+
+	return report, nil
+}
+
+// compare flattens both values to their JSON fields and lists the differing
+// ones in field order. Arrays are compared as a whole.
+func compare(old, new interface{}) ([]Change, error) {
+	oldFields, err := jsonfields.Flatten(old)
+	if err != nil {
+		return nil, fmt.Errorf("error flattening scan: %v", err)
+	}
+	newFields, err := jsonfields.Flatten(new)
+	if err != nil {
+		return nil, fmt.Errorf("error flattening scan: %v", err)
+	}
+
+	var changes []Change
+	for field, oldValue := range oldFields {
+		if newValue, ok := newFields[field]; !ok || !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, Change{Field: field, Old: oldValue, New: newFields[field]})
+		}
+	}
+	for field, newValue := range newFields {
+		if _, ok := oldFields[field]; !ok {
+			changes = append(changes, Change{Field: field, New: newValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes, nil
+}
+
+// rate returns the severity of change according to severityRules.
+func rate(change Change) token.Severity {
+	for _, rule := range severityRules {
+		if ok, _ := path.Match(rule.pattern, change.Field); !ok {
+			continue
+		}
+		if severity := rule.rate(change.Old, change.New); severity != "" {
+			return severity
+		}
+	}
+	return token.SeverityLow
+}
+
+// becameTrue rates a boolean turning true; absent booleans are false.
+func becameTrue(severity token.Severity) func(old, new interface{}) token.Severity {
+	return func(old, new interface{}) token.Severity {
+		if !truthy(old) && truthy(new) {
+			return severity
+		}
+		return ""
+	}
+}
+
+// becameFalse rates a boolean turning false; absent booleans are false.
+func becameFalse(severity token.Severity) func(old, new interface{}) token.Severity {
+	return func(old, new interface{}) token.Severity {
+		if truthy(old) && !truthy(new) {
+			return severity
+		}
+		return ""
+	}
+}
+
+// increased rates a number growing; absent numbers are zero.
+func increased(severity token.Severity) func(old, new interface{}) token.Severity {
+	return func(old, new interface{}) token.Severity {
+		if number(new) > number(old) {
+			return severity
+		}
+		return ""
+	}
+}
+
+// decreased rates a number shrinking; absent numbers are zero.
+func decreased(severity token.Severity) func(old, new interface{}) token.Severity {
+	return func(old, new interface{}) token.Severity {
+		if number(new) < number(old) {
+			return severity
+		}
+		return ""
+	}
+}
+
+// changed rates any change of a value that was set before.
+func changed(severity token.Severity) func(old, new interface{}) token.Severity {
+	return func(old, new interface{}) token.Severity {
+		if old != nil {
+			return severity
+		}
+		return ""
+	}
+}
+
+// grew rates an array gaining elements.
+func grew(severity token.Severity) func(old, new interface{}) token.Severity {
+	return func(old, new interface{}) token.Severity {
+		oldItems, _ := old.([]interface{})
+		newItems, _ := new.([]interface{})
+		if len(newItems) > len(oldItems) {
+			return severity
+		}
+		return ""
+	}
+}
+
+// worseVerdict rates a risk verdict level getting worse. An unknown or absent
+// level ranks below every assessed level, and changes from it are informational.
+func worseVerdict(severity token.Severity) func(old, new interface{}) token.Severity {
+	levels := map[interface{}]int{token.VerdictUnknown: 0, token.VerdictSafe: 1, token.VerdictCaution: 2, token.VerdictDanger: 3}
+	return func(old, new interface{}) token.Severity {
+		if levels[old] == 0 {
+			return ""
+		}
+		if levels[new] > levels[old] {
+			return severity
+		}
+		return ""
+	}
+}
+
+// truthy reports whether a JSON value is true.
+func truthy(value interface{}) bool {
+	b, _ := value.(bool)
+	return b
+}
+
+// number returns a JSON number or numeric string as a float, or zero.
+func number(value interface{}) float64 {
+	var text string
+	switch v := value.(type) {
+	case json.Number:
+		text = v.String()
+	case string:
+		text = v
+	default:
+		return 0
+	}
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
@@ -0,0 +1,81 @@
+package diff
+
+import (
+	"testing"
+
+	"github.com/s-Amine/token-scan/token"
+)
+
+// severities returns the severity of every change by field.
+func severities(t *testing.T, old, new *token.TokenInfo) map[string]token.Severity {
+	t.Helper()
+	changes, err := Tokens(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]token.Severity{}
+	for _, change := range changes {
+		got[change.Field] = change.Severity
+	}
+	return got
+}
+
+func TestVerdictChanges(t *testing.T) {
+	tests := []struct {
+		old, new string
+		want     token.Severity
+	}{
+		{token.VerdictSafe, token.VerdictCaution, token.SeverityHigh},
+		{token.VerdictCaution, token.VerdictDanger, token.SeverityHigh},
+		{token.VerdictDanger, token.VerdictSafe, token.SeverityLow},
+		{token.VerdictSafe, token.VerdictUnknown, token.SeverityLow},
+		{token.VerdictUnknown, token.VerdictSafe, token.SeverityLow},
+		{token.VerdictUnknown, token.VerdictDanger, token.SeverityLow},
+	}
+	for _, tt := range tests {
+		old := &token.TokenInfo{Risk: &token.RiskVerdict{Level: tt.old}}
+		new := &token.TokenInfo{Risk: &token.RiskVerdict{Level: tt.new}}
+		if got := severities(t, old, new)["risk.level"]; got != tt.want {
+			t.Errorf("%s to %s: got severity %q, want %q", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
+
+func TestChangesFromUnknown(t *testing.T) {
+	old := &token.TokenInfo{Risk: &token.RiskVerdict{Level: token.VerdictUnknown, Incomplete: true}}
+	new := &token.TokenInfo{
+		SellTax: "0.3",
+		Risk:    &token.RiskVerdict{Level: token.VerdictDanger, Score: 45, Factors: []token.RiskFactor{{Code: "high_sell_tax", Severity: token.SeverityHigh}}},
+	}
+	got := severities(t, old, new)
+	for _, field := range []string{"risk.level", "risk.score", "risk.incomplete"} {
+		if got[field] != token.SeverityLow {
+			t.Errorf("%s: got severity %q, want %q", field, got[field], token.SeverityLow)
+		}
+	}
+	// Fields with data of their own are still rated
+	if got["sell_tax"] != token.SeverityHigh {
+		t.Errorf("sell_tax: got severity %q, want %q", got["sell_tax"], token.SeverityHigh)
+	}
+
+	// The same changes from an assessed verdict are not informational
+	old.Risk = &token.RiskVerdict{Level: token.VerdictSafe, Score: 5}
+	got = severities(t, old, new)
+	if got["risk.level"] != token.SeverityHigh || got["risk.score"] != token.SeverityMedium {
+		t.Errorf("got risk.level %q and risk.score %q, want %q and %q", got["risk.level"], got["risk.score"], token.SeverityHigh, token.SeverityMedium)
+	}
+}
+
+func TestReportSeverity(t *testing.T) {
+	report := &Report{Changes: []Change{
+		{Field: "token_name", Severity: token.SeverityLow},
+		{Field: "is_honeypot", Severity: token.SeverityCritical},
+		{Field: "sell_tax", Severity: token.SeverityHigh},
+	}}
+	if got := report.Severity(); got != token.SeverityCritical {
+		t.Errorf("got severity %q, want %q", got, token.SeverityCritical)
+	}
+	if got := (&Report{}).Severity(); got != "" {
+		t.Errorf("got severity %q for no changes, want none", got)
+	}
+}
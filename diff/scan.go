@@ -0,0 +1,83 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/s-Amine/token-scan/envelope"
+	"github.com/s-Amine/token-scan/history"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/token"
+)
+
+// providerModes maps the single provider scan modes to provider names.
+var providerModes = map[string]string{
+	"goplus":     multiscan.ProviderGoPlus,
+	"ishoneypot": multiscan.ProviderHoneypot,
+	"quickIntel": multiscan.ProviderQuickIntel,
+}
+
+// Scan is one side of a diff: the unified token information, when known, and
+// the raw provider responses keyed by provider name.
+type Scan struct {
+	Info *token.TokenInfo
+	Raw  map[string]json.RawMessage
+}
+
+// FromRecord returns the scan stored in a history record.
+func FromRecord(record *history.Record) (*Scan, error) {
+	if provider, ok := providerModes[record.Mode]; ok {
+		return &Scan{Raw: map[string]json.RawMessage{provider: record.Result}}, nil
+	}
+	return parseResult(record.Result)
+}
+
+// ParseScan parses a history record, an output envelope or the JSON output
+// of a multiscan, with or without raw provider responses.
+func ParseScan(data []byte) (*Scan, error) {
+	var probe struct {
+		Mode          string          `json:"mode"`
+		SchemaVersion string          `json:"schema_version"`
+		Kind          string          `json:"kind"`
+		Result        json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("error parsing scan: %v", err)
+	}
+	if probe.SchemaVersion != "" && probe.Kind != "" && probe.Result != nil {
+		if probe.Kind == envelope.KindMultiscan {
+			return parseResult(probe.Result)
+		}
+		return &Scan{Raw: map[string]json.RawMessage{probe.Kind: probe.Result}}, nil
+	}
+	if probe.Mode != "" && probe.Result != nil {
+		record := &history.Record{}
+		if err := json.Unmarshal(data, record); err != nil {
+			return nil, fmt.Errorf("error parsing history record: %v", err)
+		}
+		return FromRecord(record)
+	}
+	return parseResult(data)
+}
+
+// parseResult parses a multiscan result or a bare TokenInfo.
+func parseResult(data []byte) (*Scan, error) {
+	var result struct {
+		Unified *token.TokenInfo           `json:"unified"`
+		Raw     map[string]json.RawMessage `json:"raw"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("error parsing scan: %v", err)
+	}
+	if result.Unified != nil {
+		// Provider errors are not a response to compare.
+		delete(result.Raw, "errors")
+		return &Scan{Info: result.Unified, Raw: result.Raw}, nil
+	}
+
+	info := &token.TokenInfo{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("error parsing scan: %v", err)
+	}
+	return &Scan{Info: info}, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/s-Amine/token-scan/diff"
+	"github.com/s-Amine/token-scan/history"
+)
+
+// diffCommand implements the diff command. It compares two scans, each given
+// as a history record ID or a JSON file, or the last two stored scans of a
+// token.
+func diffCommand(flags *flag.FlagSet) func(args []string) {
+	tokenHash := flags.String("token", "", "Compare the last two stored multiscans of this token hash")
+
+	return func(args []string) {
+		if (*tokenHash == "") == (len(args) != 2) {
+			usageError(flags, "two scans (history record IDs or JSON files) or -token are required")
+		}
+
+		cfg := loadConfig()
+		store, err := history.Open(cfg.History.Path)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var old, new *diff.Scan
+		if *tokenHash != "" {
+			old, new, err = lastTwoScans(store, *tokenHash)
+		} else {
+			if old, err = loadScan(store, args[0]); err == nil {
+				new, err = loadScan(store, args[1])
+			}
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		report, err := diff.Scans(old, new)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		printResult(report)
+	}
+}
+
+// loadScan reads a scan from a JSON file, or from the history when no such
+// file exists.
+func loadScan(store *history.Store, ref string) (*diff.Scan, error) {
+	data, err := os.ReadFile(ref)
+	if err == nil {
+		return diff.ParseScan(data)
+	}
+	if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("error reading %s: %v", ref, err)
+	}
+
+	record, err := store.Get(ref)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", ref, err)
+	}
+	return diff.FromRecord(record)
+}
+
+// lastTwoScans returns the two most recent stored multiscans of a token.
+func lastTwoScans(store *history.Store, tokenHash string) (*diff.Scan, *diff.Scan, error) {
+	records, err := store.Query(history.Query{Address: tokenHash, Mode: "multiscan", Limit: 2})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) < 2 {
+		return nil, nil, fmt.Errorf("fewer than two multiscans of %s in history", tokenHash)
+	}
+
+	old, err := diff.FromRecord(&records[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	new, err := diff.FromRecord(&records[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	return old, new, nil
+}
@@ -0,0 +1,181 @@
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/decode"
+	"github.com/s-Amine/token-scan/scanners/goplus"
+	"github.com/s-Amine/token-scan/scanners/ishoneypot"
+	"github.com/s-Amine/token-scan/scanners/quickintel"
+)
+
+// DefaultCanary is the token scanned by Run on Ethereum when no canary is
+// given (USDC).
+const DefaultCanary = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+
+// canaries are the tokens scanned on the other chains when no canary is given
+// (native USDC, or Binance-Peg USDC on BSC).
+var canaries = map[string]string{
+	chain.BSC.Name:       "0x8ac76a51cc950d9822d68b83fe1ad97b32cd580d",
+	chain.Base.Name:      "0x833589fcd6edb6e08f4c7c32d4f71b54bda02913",
+	chain.Arbitrum.Name:  "0xaf88d065e77c8cc2239327c5edb3a432268e5831",
+	chain.Polygon.Name:   "0x3c499c542cef5e3811e1192ce70d8cc03d5c3359",
+	chain.Avalanche.Name: "0xb97ef9ef8734c71904d8002f8b6bc66dd9c48a6e",
+}
+
+// Check statuses.
+const (
+	StatusOK    = "ok"
+	StatusError = "error"
+)
+
+// Check represents the health of one provider.
+type Check struct {
+	Provider       string           `json:"provider"`
+	Status         string           `json:"status"`
+	LatencyMs      int64            `json:"latency_ms"`
+	Error          string           `json:"error,omitempty"`
+	UnknownFields  []string         `json:"unknown_fields,omitempty"`
+	MissingFields  []string         `json:"missing_fields,omitempty"`
+	DecodeProblems []decode.Problem `json:"decode_problems,omitempty"`
+}
+
+// Healthy reports whether every check succeeded.
+func Healthy(checks []Check) bool {
+	for _, check := range checks {
+		if check.Status != StatusOK {
+			return false
+		}
+	}
+	return true
+}
+
+// Run scans canary on c with each provider in turn and reports its status,
+// latency and schema drift. Drift tracking is enabled for the duration of the
+// run. honeypot.is is left out on the chains it does not support. On the basic
+// QuickIntel tier, the premium fields are not reported missing.
+func Run(c chain.Chain, canary string) []Check {
+	if canary == "" {
+		canary = DefaultCanary
+		if address, ok := canaries[c.Name]; ok {
+			canary = address
+		}
+	}
+
+	wasEnabled := decode.DriftTrackingEnabled()
+	decode.EnableDriftTracking(true)
+	defer decode.EnableDriftTracking(wasEnabled)
+
+	checks := []Check{
+		run(goplus.Name, func() ([]decode.Problem, error) {
+			_, err := goplus.ScanChain(c, canary)
+			return nil, err
+		}),
+	}
+	if c.Honeypot {
+		checks = append(checks, run(ishoneypot.Name, func() ([]decode.Problem, error) {
+			response, err := ishoneypot.ScanChain(c, canary)
+			return response.DecodeProblems, err
+		}))
+	}
+	checks = append(checks, run(quickintel.Name, func() ([]decode.Problem, error) {
+		response, err := quickintel.ScanChain(c, canary)
+		return response.DecodeProblems, err
+	}))
+	if quickintel.ConfiguredTier() != quickintel.TierPremium {
+		premium := quickintel.TierFields(quickintel.TierPremium)
+		for i := range checks {
+			if checks[i].Provider == quickintel.Name {
+				checks[i].MissingFields = without(checks[i].MissingFields, premium)
+			}
+		}
+	}
+	return checks
+}
+
+// run times scan and collects the drift it added to the statistics of provider.
+func run(provider string, scan func() ([]decode.Problem, error)) Check {
+	before := decode.DriftSummary()[provider]
+
+	start := time.Now()
+	problems, err := scan()
+	check := Check{
+		Provider:       provider,
+		Status:         StatusOK,
+		LatencyMs:      time.Since(start).Milliseconds(),
+		DecodeProblems: problems,
+	}
+	if err != nil {
+		check.Status = StatusError
+		check.Error = err.Error()
+	}
+
+	after := decode.DriftSummary()[provider]
+	check.UnknownFields = increased(before.UnknownFields, after.UnknownFields)
+	check.MissingFields = increased(before.MissingFields, after.MissingFields)
+
+	return check
+}
+
+// increased returns the fields whose count grew between before and after.
+func increased(before, after map[string]int) []string {
+	var fields []string
+	for field, count := range after {
+		if count > before[field] {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// without returns fields minus the excluded ones.
+func without(fields, excluded []string) []string {
+	skip := make(map[string]bool, len(excluded))
+	for _, field := range excluded {
+		skip[field] = true
+	}
+	var kept []string
+	for _, field := range fields {
+		if !skip[field] {
+			kept = append(kept, field)
+		}
+	}
+	return kept
+}
+
+// Print writes a human readable report of checks to w.
+func Print(w io.Writer, checks []Check) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROVIDER\tSTATUS\tLATENCY\tUNKNOWN\tMISSING\tDECODE PROBLEMS")
+	for _, check := range checks {
+		fmt.Fprintf(tw, "%s\t%s\t%dms\t%d\t%d\t%d\n", check.Provider, check.Status, check.LatencyMs,
+			len(check.UnknownFields), len(check.MissingFields), len(check.DecodeProblems))
+	}
+	tw.Flush()
+
+	for _, check := range checks {
+		if check.Error != "" {
+			fmt.Fprintf(w, "\n%s error: %s\n", check.Provider, check.Error)
+		}
+		printList(w, check.Provider+" unknown fields", check.UnknownFields)
+		printList(w, check.Provider+" missing fields", check.MissingFields)
+		for _, problem := range check.DecodeProblems {
+			fmt.Fprintf(w, "\n%s decode problem: %s\n", check.Provider, problem)
+		}
+	}
+}
+
+// printList writes a titled list when it is not empty.
+func printList(w io.Writer, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s:\n  %s\n", title, strings.Join(items, "\n  "))
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/s-Amine/token-scan/doctor"
+)
+
+// doctorCommand implements the doctor command. It checks every provider with
+// a canary token on the -chain chain and exits with status 1 when one of them
+// is unhealthy.
+func doctorCommand(flags *flag.FlagSet) func(args []string) {
+	return func(args []string) {
+		if len(args) > 1 {
+			usageError(flags, "at most one canary token hash is accepted")
+		}
+		var canary string
+		if len(args) == 1 {
+			canary = args[0]
+		}
+
+		loadConfig()
+		checks := doctor.Run(globals.scanChain, canary)
+		// The checks are printed as text unless an output format is selected
+		if globals.output == "" {
+			doctor.Print(os.Stdout, checks)
+		} else {
+			printResult(checks)
+		}
+		if !doctor.Healthy(checks) {
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package envelope
+
+import (
+	"time"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/decode"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/version"
+)
+
+// SchemaVersion is the version of the envelope and result schema. It changes
+// when a field is removed, renamed or changes type.
+const SchemaVersion = "1.0"
+
+// Result kinds. Provider kinds are the provider names of multiscan.
+const (
+	KindMultiscan  = "multiscan"
+	KindGoPlus     = multiscan.ProviderGoPlus
+	KindHoneypot   = multiscan.ProviderHoneypot
+	KindQuickIntel = multiscan.ProviderQuickIntel
+)
+
+// Envelope wraps the result of every scan mode with what was scanned, when
+// and by which providers.
+type Envelope struct {
+	SchemaVersion string      `json:"schema_version"`
+	ToolVersion   string      `json:"tool_version"`
+	Kind          string      `json:"kind"`
+	Chain         chain.Chain `json:"chain"`
+	Address       string      `json:"address"`
+	ScannedAt     time.Time   `json:"scanned_at"`
+	// Sources are the providers whose responses the result is built from.
+	Sources []string    `json:"sources"`
+	Result  interface{} `json:"result"`
+	// Errors are the messages of the providers that failed, and
+	// DecodeProblems the response fields of each provider that could not be
+	// decoded. They are only set for multiscans.
+	Errors         map[string]string           `json:"errors,omitempty"`
+	DecodeProblems map[string][]decode.Problem `json:"decode_problems,omitempty"`
+}
+
+// New wraps the result of a scan made now.
+func New(kind string, c chain.Chain, address string, sources []string, result interface{}) *Envelope {
+	if sources == nil {
+		sources = []string{}
+	}
+	return &Envelope{
+		SchemaVersion: SchemaVersion,
+		ToolVersion:   version.String(),
+		Kind:          kind,
+		Chain:         c,
+		Address:       address,
+		ScannedAt:     time.Now().UTC(),
+		Sources:       sources,
+		Result:        result,
+	}
+}
+
+// Multiscan wraps a multiscan made with raw responses; the providers that
+// responded are the sources. Unless includeRaw is set, the result is only
+// the unified token information; provider errors and decode problems are
+// kept on the envelope either way.
+func Multiscan(c chain.Chain, address string, result *multiscan.Result, includeRaw bool) *Envelope {
+	var errs map[string]string
+	if result.Raw != nil {
+		errs = result.Raw.Errors
+	}
+	var sources []string
+	for _, provider := range []string{multiscan.ProviderGoPlus, multiscan.ProviderHoneypot, multiscan.ProviderQuickIntel} {
+		if _, failed := errs[provider]; !failed {
+			sources = append(sources, provider)
+		}
+	}
+
+	env := New(KindMultiscan, c, address, sources, result.Unified)
+	env.Errors = errs
+	env.DecodeProblems = result.DecodeProblems
+	if includeRaw {
+		env.Result = result
+	}
+	if !result.ScannedAt.IsZero() {
+		env.ScannedAt = result.ScannedAt.UTC()
+	}
+	return env
+}
@@ -0,0 +1,36 @@
+package envelope
+
+import (
+	"testing"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/decode"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/token"
+)
+
+func TestMultiscanKeepsErrorsAndDecodeProblems(t *testing.T) {
+	result := &multiscan.Result{
+		Unified: &token.TokenInfo{TokenName: "Fake Token"},
+		Raw:     &multiscan.RawResponses{Errors: map[string]string{multiscan.ProviderGoPlus: "timeout"}},
+		DecodeProblems: map[string][]decode.Problem{
+			multiscan.ProviderQuickIntel: {{Field: "tokenDetails.tokenDecimals", Value: `"x"`, Reason: "not a number"}},
+		},
+	}
+
+	for _, includeRaw := range []bool{false, true} {
+		env := Multiscan(chain.Default, "0x1", result, includeRaw)
+		if env.Errors[multiscan.ProviderGoPlus] != "timeout" {
+			t.Errorf("include raw %v: got errors %v, want the GoPlus error", includeRaw, env.Errors)
+		}
+		if len(env.DecodeProblems[multiscan.ProviderQuickIntel]) != 1 {
+			t.Errorf("include raw %v: got decode problems %v, want the QuickIntel problem", includeRaw, env.DecodeProblems)
+		}
+		if len(env.Sources) != 2 {
+			t.Errorf("include raw %v: got sources %v, want the providers that responded", includeRaw, env.Sources)
+		}
+	}
+	if env := Multiscan(chain.Default, "0x1", result, false); env.Result != result.Unified {
+		t.Errorf("got result %T, want the unified token information", env.Result)
+	}
+}
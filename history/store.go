@@ -0,0 +1,294 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/token"
+)
+
+// EnvHistoryPath is the environment variable overriding the default store path.
+const EnvHistoryPath = "TOKENSCAN_HISTORY"
+
+// Config represents the history settings of the configuration file.
+type Config struct {
+	// Path is the store file; empty uses DefaultPath.
+	Path string `json:"path"`
+	// Disabled turns off recording of scans.
+	Disabled bool `json:"disabled"`
+}
+
+// ErrNotFound is returned by Get when no record has the requested ID.
+var ErrNotFound = errors.New("history record not found")
+
+// Record represents one stored scan.
+type Record struct {
+	ID        string    `json:"id"`
+	ScannedAt time.Time `json:"scanned_at"`
+	Chain     string    `json:"chain"`
+	Address   string    `json:"address"`
+	// Mode is the scan mode that produced the result, such as multiscan or goplus.
+	Mode string `json:"mode"`
+	// Verdict and Flags summarize the risk assessment of multiscan results.
+	Verdict string          `json:"verdict,omitempty"`
+	Flags   []string        `json:"flags,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+}
+
+// NewRecord builds a record of result, stamped with the scan time of multiscan
+// results and with the current time otherwise. The verdict and flags are
+// filled for multiscan results.
+func NewRecord(mode, chain, address string, result interface{}) (*Record, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling scan result: %v", err)
+	}
+
+	now := time.Now().UTC()
+	record := &Record{
+		ID:        strconv.FormatInt(now.UnixNano(), 36),
+		ScannedAt: now,
+		Chain:     chain,
+		Address:   strings.ToLower(address),
+		Mode:      mode,
+		Result:    data,
+	}
+
+	var info *token.TokenInfo
+	switch r := result.(type) {
+	case *token.TokenInfo:
+		info = r
+	case *multiscan.Result:
+		info = r.Unified
+		if !r.ScannedAt.IsZero() {
+			record.ScannedAt = r.ScannedAt.UTC()
+		}
+	}
+	if info != nil && info.Risk != nil {
+		record.Verdict = info.Risk.Level
+		for _, factor := range info.Risk.Factors {
+			record.Flags = append(record.Flags, factor.Code)
+		}
+	}
+
+	return record, nil
+}
+
+// TokenInfo decodes the unified token information of a multiscan record.
+// It returns nil for records of single provider scans.
+func (r *Record) TokenInfo() (*token.TokenInfo, error) {
+	if r.Mode != "multiscan" {
+		return nil, nil
+	}
+	var wrapped struct {
+		Unified *token.TokenInfo `json:"unified"`
+	}
+	if err := json.Unmarshal(r.Result, &wrapped); err == nil && wrapped.Unified != nil {
+		return wrapped.Unified, nil
+	}
+	info := &token.TokenInfo{}
+	if err := json.Unmarshal(r.Result, info); err != nil {
+		return nil, fmt.Errorf("error decoding record %s: %v", r.ID, err)
+	}
+	return info, nil
+}
+
+// Query selects records. Zero fields do not filter.
+type Query struct {
+	Address string
+	Chain   string
+	Mode    string
+	Since   time.Time
+	Until   time.Time
+	Verdict string
+	// Flag selects records whose risk factors include the given code.
+	Flag string
+	// Limit keeps only the most recent records.
+	Limit int
+}
+
+// matches reports whether record satisfies the query.
+func (q Query) matches(record *Record) bool {
+	if q.Address != "" && !strings.EqualFold(q.Address, record.Address) {
+		return false
+	}
+	if q.Chain != "" && q.Chain != record.Chain {
+		return false
+	}
+	if q.Mode != "" && q.Mode != record.Mode {
+		return false
+	}
+	if !q.Since.IsZero() && record.ScannedAt.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && record.ScannedAt.After(q.Until) {
+		return false
+	}
+	if q.Verdict != "" && q.Verdict != record.Verdict {
+		return false
+	}
+	if q.Flag != "" {
+		for _, flag := range record.Flags {
+			if flag == q.Flag {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Store is a scan history kept in a JSON Lines file, one record per line.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// stores are the stores opened by the process, by absolute path, so that
+// every writer of a file shares its lock.
+var (
+	storesMu sync.Mutex
+	stores   = make(map[string]*Store)
+)
+
+// DefaultPath returns the store path from TOKENSCAN_HISTORY, falling back to
+// ~/.token-scan/history.jsonl.
+func DefaultPath() string {
+	if path := os.Getenv(EnvHistoryPath); path != "" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".token-scan", "history.jsonl")
+	}
+	return filepath.Join(home, ".token-scan", "history.jsonl")
+}
+
+// Open opens the store at path, creating its directory when needed. An empty
+// path uses DefaultPath. Opening the same file again returns the same store.
+func Open(path string) (*Store, error) {
+	if path == "" {
+		path = DefaultPath()
+	}
+	key, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving history path: %v", err)
+	}
+
+	storesMu.Lock()
+	defer storesMu.Unlock()
+
+	if store, ok := stores[key]; ok {
+		return store, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating history directory: %v", err)
+	}
+	store := &Store{path: path}
+	stores[key] = store
+	return store, nil
+}
+
+// Path returns the file backing the store.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Add appends record to the store.
+func (s *Store) Add(record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error marshaling history record: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening history: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("error writing history: %v", err)
+	}
+	return nil
+}
+
+// Query returns the records matching q, oldest first.
+func (s *Store) Query(q Query) ([]Record, error) {
+	var records []Record
+	err := s.scan(func(record *Record) bool {
+		if q.matches(record) {
+			records = append(records, *record)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if q.Limit > 0 && len(records) > q.Limit {
+		records = records[len(records)-q.Limit:]
+	}
+	return records, nil
+}
+
+// Get returns the record with the given ID.
+func (s *Store) Get(id string) (*Record, error) {
+	var found *Record
+	err := s.scan(func(record *Record) bool {
+		if record.ID == id {
+			found = record
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrNotFound
+	}
+	return found, nil
+}
+
+// scan calls fn for every record in file order until fn returns false.
+func (s *Store) scan(fn func(record *Record) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error opening history: %v", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		record := &Record{}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return fmt.Errorf("error parsing history line %d: %v", line, err)
+		}
+		if !fn(record) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,141 @@
+package history
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/token"
+)
+
+func TestOpenSharesStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	first, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Open(filepath.Join(filepath.Dir(path), ".", "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Fatal("got two stores for the same file, want one shared store")
+	}
+}
+
+func TestConcurrentAdd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	const writers, records = 8, 25
+
+	// Every writer opens the store itself, as each command does
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store, err := Open(path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for i := 0; i < records; i++ {
+				record, err := NewRecord("multiscan", "1", "0xabc", &token.TokenInfo{TokenName: "Fake Token", Risk: &token.RiskVerdict{Level: token.VerdictSafe}})
+				if err == nil {
+					err = store.Add(record)
+				}
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Query(Query{})
+	if err != nil {
+		t.Fatalf("reading history: %v", err)
+	}
+	if len(got) != writers*records {
+		t.Errorf("got %d records, want %d", len(got), writers*records)
+	}
+}
+
+func TestNewRecordScanTime(t *testing.T) {
+	scannedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	result := &multiscan.Result{Unified: &token.TokenInfo{Risk: &token.RiskVerdict{Level: token.VerdictSafe}}, ScannedAt: scannedAt}
+	record, err := NewRecord("multiscan", "1", "0xABC", result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !record.ScannedAt.Equal(scannedAt) || record.ScannedAt.Location() != time.UTC {
+		t.Errorf("got scan time %v, want %v in UTC", record.ScannedAt, scannedAt)
+	}
+	if record.Address != "0xabc" || record.Verdict != token.VerdictSafe {
+		t.Errorf("got address %q and verdict %q, want 0xabc and %q", record.Address, record.Verdict, token.VerdictSafe)
+	}
+}
+
+func TestQuery(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	records := []Record{
+		{ID: "a", ScannedAt: start, Chain: "1", Address: "0xaaa", Mode: "multiscan", Verdict: "safe"},
+		{ID: "b", ScannedAt: start.Add(time.Hour), Chain: "56", Address: "0xaaa", Mode: "goplus"},
+		{ID: "c", ScannedAt: start.Add(2 * time.Hour), Chain: "1", Address: "0xbbb", Mode: "multiscan", Verdict: "danger", Flags: []string{"honeypot"}},
+		{ID: "d", ScannedAt: start.Add(3 * time.Hour), Chain: "1", Address: "0xaaa", Mode: "multiscan", Verdict: "warning", Flags: []string{"high_sell_tax"}},
+	}
+	for i := range records {
+		if err := store.Add(&records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  []string
+	}{
+		{"all, oldest first", Query{}, []string{"a", "b", "c", "d"}},
+		{"address ignores case", Query{Address: "0xAAA"}, []string{"a", "b", "d"}},
+		{"chain", Query{Chain: "1"}, []string{"a", "c", "d"}},
+		{"mode", Query{Mode: "goplus"}, []string{"b"}},
+		{"since is inclusive", Query{Since: start.Add(time.Hour)}, []string{"b", "c", "d"}},
+		{"until is inclusive", Query{Until: start.Add(time.Hour)}, []string{"a", "b"}},
+		{"verdict", Query{Verdict: "danger"}, []string{"c"}},
+		{"flag", Query{Flag: "high_sell_tax"}, []string{"d"}},
+		{"limit keeps the most recent", Query{Limit: 2}, []string{"c", "d"}},
+		{"limit applies after filters", Query{Address: "0xaaa", Chain: "1", Limit: 1}, []string{"d"}},
+		{"limit above the count", Query{Chain: "56", Limit: 5}, []string{"b"}},
+		{"no match", Query{Address: "0xccc"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Query(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			for _, record := range got {
+				ids = append(ids, record.ID)
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("got records %v, want %v", ids, tt.want)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("got records %v, want %v", ids, tt.want)
+				}
+			}
+		})
+	}
+}
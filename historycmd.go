@@ -0,0 +1,91 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/s-Amine/token-scan/history"
+)
+
+// historyCommand implements the history list and show commands. The list is
+// filtered by chain when -chain is given.
+func historyCommand(flags *flag.FlagSet) func(args []string) {
+	address := flags.String("token", "", "Only list scans of this token hash")
+	mode := flags.String("mode", "", "Only list scans made in this mode")
+	since := flags.String("since", "", "Only list scans after this time (RFC 3339) or duration ago (e.g. 24h)")
+	until := flags.String("until", "", "Only list scans before this time (RFC 3339) or duration ago")
+	verdict := flags.String("verdict", "", "Only list scans with this verdict: safe, caution, danger or unknown")
+	riskFlag := flags.String("flag", "", "Only list scans raising this risk factor code")
+	limit := flags.Int("limit", 0, "Only list the most recent scans")
+
+	return func(args []string) {
+		if len(args) == 0 {
+			usageError(flags, "history list or history show is required")
+		}
+
+		cfg := loadConfig()
+		store, err := history.Open(cfg.History.Path)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		switch args[0] {
+		case "list":
+			query := history.Query{
+				Address: *address,
+				Mode:    *mode,
+				Verdict: *verdict,
+				Flag:    *riskFlag,
+				Limit:   *limit,
+			}
+			if globals.chain != "" {
+				query.Chain = globals.scanChain.ID
+			}
+			if query.Since, err = parseTime(*since); err != nil {
+				fmt.Printf("Error: invalid -since: %v\n", err)
+				os.Exit(1)
+			}
+			if query.Until, err = parseTime(*until); err != nil {
+				fmt.Printf("Error: invalid -until: %v\n", err)
+				os.Exit(1)
+			}
+			records, err := store.Query(query)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			// Results are left out of the listing; use show to see them.
+			for i := range records {
+				records[i].Result = nil
+			}
+			printResult(records)
+		case "show":
+			if len(args) != 2 {
+				usageError(flags, "history show requires one record ID")
+			}
+			record, err := store.Get(args[1])
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			printResult(record)
+		default:
+			usageError(flags, "unknown history command %q", args[0])
+		}
+	}
+}
+
+// parseTime parses an RFC 3339 time or a duration before now. An empty value
+// yields the zero time.
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,80 @@
+// Package jsonfields flattens JSON documents to their leaf fields, keyed by
+// dotted path, for the table formats and the comparison of scans.
+package jsonfields
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Flatten returns the leaf values of the JSON encoding of value keyed by their
+// dotted path. Numbers are json.Number, and arrays are kept whole as leaves.
+func Flatten(value interface{}) (map[string]interface{}, error) {
+	data, ok := value.(json.RawMessage)
+	if !ok {
+		var err error
+		if data, err = json.Marshal(value); err != nil {
+			return nil, fmt.Errorf("error marshaling value: %v", err)
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, fmt.Errorf("error decoding value: %v", err)
+	}
+
+	fields := map[string]interface{}{}
+	flattenInto(fields, "", tree)
+	return fields, nil
+}
+
+// flattenInto adds the leaves of node under prefix to fields.
+func flattenInto(fields map[string]interface{}, prefix string, node interface{}) {
+	object, ok := node.(map[string]interface{})
+	if !ok {
+		if node != nil {
+			fields[prefix] = node
+		}
+		return
+	}
+	for key, child := range object {
+		field := key
+		if prefix != "" {
+			field = prefix + "." + key
+		}
+		flattenInto(fields, field, child)
+	}
+}
+
+// Format formats a flattened value as text. Arrays are shown as JSON.
+func Format(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// SortedKeys returns the keys of a string-keyed map in order.
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -4,63 +4,391 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
-	"github.com/s-Amine/token-scan/scanners/goplus"
-	"github.com/s-Amine/token-scan/scanners/ishoneypot"
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/config"
+	"github.com/s-Amine/token-scan/decode"
+	"github.com/s-Amine/token-scan/history"
+	"github.com/s-Amine/token-scan/output"
 	"github.com/s-Amine/token-scan/scanners/multiscan"
-	"github.com/s-Amine/token-scan/scanners/quickintel"
 )
 
-func main() {
-	// Define command-line flags
-	mode := flag.String("mode", "", "Mode of operation: multiscan, goplus, ishoneypot, or quickIntel")
-	tokenHash := flag.String("token", "", "Token hash to scan")
-	flag.Parse()
+// command is a token-scan subcommand.
+type command struct {
+	name string
+	// args is the synopsis of the positional arguments.
+	args    string
+	summary string
+	// words are the positional arguments completed by shells.
+	words []string
+	// files reports whether the positional arguments may be file names.
+	files bool
+	// setup registers the flags of the command and returns the function
+	// running it with the positional arguments.
+	setup func(flags *flag.FlagSet) func(args []string)
+}
+
+// commands lists the subcommands in the order they are shown in the help.
+func commands() []command {
+	return []command{
+		{name: "scan", args: "<token_hash>", summary: "Scan a token with every provider, or a single one with -mode", setup: scanCommand},
+		{name: "batch", args: "[token_hash...]", summary: "Scan a list of tokens and alert on the risky ones", setup: batchCommand},
+		{name: "watch", args: "[token_hash...]", summary: "Rescan tokens on a schedule and alert on changes", setup: watchCommand},
+		{name: "diff", args: "<old> <new>", summary: "Compare two scans or the last two scans of a token", files: true, setup: diffCommand},
+		{name: "serve", summary: "Serve scans over HTTP and alert on the risky tokens", setup: serveCommand},
+		{name: "history", args: "list | show <id>", summary: "List and show recorded scans", words: []string{"list", "show"}, setup: historyCommand},
+		{name: "config", args: "show | path", summary: "Show the effective configuration", words: []string{"show", "path"}, setup: configCommand},
+		{name: "doctor", args: "[canary]", summary: "Check the health and schema drift of every provider", setup: doctorCommand},
+		{name: "version", summary: "Print the version", setup: versionCommand},
+		{name: "compare", args: "<token_hash> <token_hash>...", summary: "Compare tokens side by side", setup: compareCommand},
+		{name: "report", args: "[token_hash...]", summary: "Render an HTML report of tokens", setup: reportCommand},
+		{name: "bot", summary: "Answer Telegram messages with risk summaries", setup: botCommand},
+		{name: "schema", summary: "Print the JSON Schema of the output", setup: schemaCommand},
+		{name: "completion", args: "bash | zsh | fish", summary: "Print a shell completion script", words: completionShells, setup: completionCommand},
+	}
+}
+
+// findCommand returns the command called name.
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// globalOptions are the flags accepted before the command name and by every
+// command.
+type globalOptions struct {
+	chain    string
+	output   string
+	template string
+	config   string
+	verbose  bool
+	quiet    bool
+
+	// scanChain is the chain parsed from the -chain flag.
+	scanChain chain.Chain
+}
+
+// globals holds the global flags of the running command.
+var globals globalOptions
+
+// globalFlags are the names of the global flags.
+var globalFlags = map[string]bool{"chain": true, "output": true, "template": true, "config": true, "v": true, "q": true}
+
+// register adds the global flags to flags, defaulting to the values already
+// given before the command name.
+func (g *globalOptions) register(flags *flag.FlagSet) {
+	flags.StringVar(&g.chain, "chain", g.chain, "Chain of the tokens: "+chainNames()+" (default "+chain.Default.Name+")")
+	flags.StringVar(&g.output, "output", g.output, "Output format: "+strings.Join(output.Formats, ", ")+" (default json)")
+	flags.StringVar(&g.template, "template", g.template, "Go text/template for the template output format, inline or @file")
+	flags.StringVar(&g.config, "config", g.config, "Path to a JSON configuration file (defaults to $TOKENSCAN_CONFIG)")
+	flags.BoolVar(&g.verbose, "v", g.verbose, "Report progress and provider errors on stderr")
+	flags.BoolVar(&g.quiet, "q", g.quiet, "Only report errors on stderr")
+}
 
-	if *mode == "" {
-		flag.PrintDefaults()
+// apply validates the global flags and selects the output format.
+func (g *globalOptions) apply() {
+	if g.verbose && g.quiet {
+		fmt.Println("Error: -v and -q cannot be used together")
 		os.Exit(1)
 	}
+	c, err := chain.Parse(g.chain)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	g.scanChain = c
+	setOutput(g.output, g.template)
+}
 
-	if *tokenHash == "" {
-		fmt.Println("Error: Token hash is required")
-		flag.PrintDefaults()
+// chainNames lists the names of the supported chains.
+func chainNames() string {
+	names := make([]string, len(chain.All))
+	for i, c := range chain.All {
+		names[i] = c.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func main() {
+	args := os.Args[1:]
+	if legacy, ok := legacyArgs(args); ok {
+		fmt.Fprintln(os.Stderr, "Warning: -mode and -token are deprecated; use \"token-scan scan [-mode <mode>] <token_hash>\" or \"token-scan doctor\"")
+		args = legacy
+	}
+
+	top := flag.NewFlagSet("token-scan", flag.ExitOnError)
+	globals.register(top)
+	top.Usage = func() { printUsage(top) }
+	top.Parse(args)
+
+	if top.NArg() == 0 {
+		top.Usage()
 		os.Exit(1)
 	}
+	name, args := top.Arg(0), top.Args()[1:]
+
+	if name == "help" {
+		if len(args) == 0 {
+			top.SetOutput(os.Stdout)
+			top.Usage()
+			return
+		}
+		cmd, ok := findCommand(args[0])
+		if !ok {
+			fmt.Printf("Error: unknown command %q\n", args[0])
+			os.Exit(1)
+		}
+		flags, _ := newFlagSet(cmd)
+		flags.SetOutput(os.Stdout)
+		flags.Usage()
+		return
+	}
 
-	var result interface{}
-	var err error
-
-	switch *mode {
-	case "multiscan":
-		result = multiscan.Scan(*tokenHash)
-	case "goplus":
-		result, err = goplus.Scan(*tokenHash)
-	case "ishoneypot":
-		result, err = ishoneypot.Scan(*tokenHash)
-	case "quickIntel":
-		result, err = quickintel.Scan(*tokenHash)
-	default:
-		fmt.Println("Error: Invalid mode specified")
-		flag.PrintDefaults()
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Printf("Error: unknown command %q; run \"token-scan help\" for the list of commands\n", name)
 		os.Exit(1)
 	}
+	flags, run := newFlagSet(cmd)
+	positional := parseArgs(flags, args)
+	globals.apply()
+	run(positional)
+}
+
+// newFlagSet sets up cmd and returns its flag set, with the global flags
+// registered and a usage message listing the flags of the command apart from
+// them, and the function running it.
+func newFlagSet(cmd command) (*flag.FlagSet, func(args []string)) {
+	flags := flag.NewFlagSet("token-scan "+cmd.name, flag.ExitOnError)
+	globals.register(flags)
+	flags.Usage = func() {
+		out := flags.Output()
+		fmt.Fprintf(out, "Usage: token-scan %s [flags] %s\n\n%s.\n", cmd.name, cmd.args, cmd.summary)
+
+		own := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+		own.SetOutput(out)
+		flags.VisitAll(func(f *flag.Flag) {
+			if !globalFlags[f.Name] {
+				own.Var(f.Value, f.Name, f.Usage)
+			}
+		})
+		if hasFlags(own) {
+			fmt.Fprintln(out, "\nFlags:")
+			own.PrintDefaults()
+		}
+		fmt.Fprintln(out, "\nGlobal flags:")
+		printGlobalFlags(out)
+	}
+	return flags, cmd.setup(flags)
+}
+
+// hasFlags reports whether flags defines any flag.
+func hasFlags(flags *flag.FlagSet) bool {
+	found := false
+	flags.VisitAll(func(*flag.Flag) { found = true })
+	return found
+}
+
+// printGlobalFlags prints the defaults of the global flags to out.
+func printGlobalFlags(out io.Writer) {
+	flags := flag.NewFlagSet("global", flag.ContinueOnError)
+	flags.SetOutput(out)
+	defaults := globalOptions{}
+	defaults.register(flags)
+	flags.PrintDefaults()
+}
+
+// printUsage prints the top-level help: the commands and the global flags.
+func printUsage(top *flag.FlagSet) {
+	out := top.Output()
+	fmt.Fprintln(out, "Usage: token-scan [global flags] <command> [flags] [arguments]")
+	fmt.Fprintln(out, "\nCommands:")
+	for _, cmd := range commands() {
+		fmt.Fprintf(out, "  %-11s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(out, "  %-11s %s\n", "help", "Show the help of a command")
+	fmt.Fprintln(out, "\nGlobal flags:")
+	printGlobalFlags(out)
+	fmt.Fprintln(out, "\nRun \"token-scan help <command>\" for the flags of a command.")
+}
+
+// parseArgs parses flags given before, between and after the positional
+// arguments, which it returns. Arguments after "--" are all positional.
+func parseArgs(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		flags.Parse(args)
+		rest := flags.Args()
+		if len(rest) < len(args) && args[len(args)-len(rest)-1] == "--" {
+			return append(positional, rest...)
+		}
+		if len(rest) == 0 {
+			return positional
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// usageError reports a misuse of a command with its usage and exits.
+func usageError(flags *flag.FlagSet, format string, args ...interface{}) {
+	fmt.Printf("Error: "+format+"\n", args...)
+	flags.Usage()
+	os.Exit(1)
+}
+
+// legacyValueFlags are the flags of the former invocation taking a value.
+var legacyValueFlags = map[string]bool{"mode": true, "token": true, "config": true, "record": true, "replay": true, "output": true, "template": true, "chain": true}
+
+// legacyArgs translates the former "-mode <mode> -token <token_hash>"
+// invocation into the scan and doctor commands. It reports false when args
+// do not use -mode or name a command before it.
+func legacyArgs(args []string) ([]string, bool) {
+	found := false
+	for i := 0; i < len(args) && !found; i++ {
+		if !strings.HasPrefix(args[i], "-") {
+			return nil, false
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		found = name == "mode"
+		if !hasValue && legacyValueFlags[name] {
+			i++
+		}
+	}
+	if !found {
+		return nil, false
+	}
 
+	var mode, tokenHash string
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || (name != "mode" && name != "token") {
+			rest = append(rest, args[i])
+			continue
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		if name == "mode" {
+			mode = value
+		} else {
+			tokenHash = value
+		}
+	}
+	if mode == "doctor" {
+		legacy := append([]string{"doctor"}, rest...)
+		if tokenHash != "" {
+			legacy = append(legacy, "--", tokenHash)
+		}
+		return legacy, true
+	}
+	legacy := append([]string{"scan", "-mode", mode}, rest...)
+	if tokenHash != "" {
+		legacy = append(legacy, "--", tokenHash)
+	}
+	return legacy, true
+}
+
+// loadConfig loads the configuration selected by -config and configures the
+// scanner packages with it.
+func loadConfig() *config.Config {
+	cfg, err := config.Load(globals.config)
 	if err != nil {
-		fmt.Printf("Error occurred during %s scan: %v\n", *mode, err)
+		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
+	if path := config.ResolvePath(globals.config); path != "" {
+		verbosef("Using configuration %s", path)
+	}
+	cfg.Apply()
+	return cfg
+}
 
-	printJSON(result)
+// infof reports progress on stderr unless -q is set.
+func infof(format string, args ...interface{}) {
+	if !globals.quiet {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
 }
 
-// printJSON prints the provided data structure as JSON to stdout
-func printJSON(data interface{}) {
-	jsonData, err := json.MarshalIndent(data, "", "  ")
+// verbosef reports details on stderr when -v is set.
+func verbosef(format string, args ...interface{}) {
+	if globals.verbose {
+		fmt.Fprintf(os.Stderr, format+"\n", args...)
+	}
+}
+
+// reportProviderErrors reports the providers whose scan of a token failed
+// when -v is set.
+func reportProviderErrors(address string, result *multiscan.Result) {
+	if result.Raw == nil {
+		return
+	}
+	for _, provider := range []string{multiscan.ProviderGoPlus, multiscan.ProviderHoneypot, multiscan.ProviderQuickIntel} {
+		if err, failed := result.Raw.Errors[provider]; failed {
+			verbosef("%s scan of %s failed: %s", provider, address, err)
+		}
+	}
+}
+
+// recordHistory stores the scan result in the history store. Failures are
+// reported on stderr without failing the scan.
+func recordHistory(path, mode string, c chain.Chain, tokenHash string, result interface{}) {
+	store, err := history.Open(path)
+	if err == nil {
+		var record *history.Record
+		record, err = history.NewRecord(mode, c.ID, tokenHash, result)
+		if err == nil {
+			err = store.Add(record)
+		}
+		if err == nil {
+			verbosef("Recorded scan %s in %s", record.ID, store.Path())
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error recording scan history: %v\n", err)
+	}
+}
+
+// printDrift prints the schema drift aggregated during the run as JSON to stderr
+func printDrift() {
+	jsonData, err := json.MarshalIndent(decode.DriftSummary(), "", "  ")
 	if err != nil {
-		fmt.Printf("Error marshalling JSON: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error marshalling JSON: %v\n", err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, string(jsonData))
+}
+
+// resultWriter renders the results printed by printResult.
+var resultWriter, _ = output.New(output.FormatJSON, "", false)
+
+// setOutput selects the output format of printResult. Tables are colored on
+// terminals unless NO_COLOR is set.
+func setOutput(format, tmpl string) {
+	_, noColor := os.LookupEnv("NO_COLOR")
+	writer, err := output.New(format, tmpl, !noColor && output.IsTerminal(os.Stdout))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	resultWriter = writer
+}
+
+// printResult prints the provided data structure to stdout in the selected
+// output format
+func printResult(data interface{}) {
+	if err := resultWriter.Write(os.Stdout, data); err != nil {
+		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
-	fmt.Println(string(jsonData))
 }
@@ -0,0 +1,129 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"text/template"
+)
+
+// Output formats.
+const (
+	FormatJSON     = "json"
+	FormatTable    = "table"
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+	FormatNDJSON   = "ndjson"
+	FormatTemplate = "template"
+)
+
+// Formats lists the accepted output formats.
+var Formats = []string{FormatJSON, FormatTable, FormatMarkdown, FormatCSV, FormatNDJSON, FormatTemplate}
+
+// Writer renders results in one output format.
+type Writer struct {
+	format   string
+	color    bool
+	template *template.Template
+}
+
+// New returns a writer for format. The template format executes tmpl, given
+// inline or, prefixed with @, as a file path; the other formats ignore it.
+// Color enables ANSI colors in the table format.
+func New(format, tmpl string, color bool) (*Writer, error) {
+	if format == "" {
+		format = FormatJSON
+	}
+	w := &Writer{format: format, color: color}
+
+	switch format {
+	case FormatJSON, FormatTable, FormatMarkdown, FormatCSV, FormatNDJSON:
+	case FormatTemplate:
+		if tmpl == "" {
+			return nil, fmt.Errorf("the template format requires a template")
+		}
+		if strings.HasPrefix(tmpl, "@") {
+			data, err := os.ReadFile(tmpl[1:])
+			if err != nil {
+				return nil, fmt.Errorf("error reading template: %v", err)
+			}
+			tmpl = string(data)
+		}
+		parsed, err := template.New("output").Funcs(templateFuncs).Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing template: %v", err)
+		}
+		w.template = parsed
+	default:
+		return nil, fmt.Errorf("unknown output format %q (expected one of %s)", format, strings.Join(Formats, ", "))
+	}
+	return w, nil
+}
+
+// Format returns the output format of the writer.
+func (w *Writer) Format() string {
+	return w.format
+}
+
+// Write renders data to out.
+func (w *Writer) Write(out io.Writer, data interface{}) error {
+	switch w.format {
+	case FormatTable:
+		return renderTerminal(out, cleanTables(tablesOf(data)), w.color)
+	case FormatMarkdown:
+		return renderMarkdown(out, cleanTables(tablesOf(data)))
+	case FormatCSV:
+		return renderCSV(out, cleanTables(tablesOf(data)))
+	case FormatNDJSON:
+		return writeNDJSON(out, data)
+	case FormatTemplate:
+		if err := w.template.Execute(out, data); err != nil {
+			return fmt.Errorf("error executing template: %v", err)
+		}
+		_, err := fmt.Fprintln(out)
+		return err
+	}
+
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling JSON: %v", err)
+	}
+	_, err = fmt.Fprintln(out, string(jsonData))
+	return err
+}
+
+// writeNDJSON writes each element of a slice, or data itself, as one line of
+// compact JSON.
+func writeNDJSON(out io.Writer, data interface{}) error {
+	encoder := json.NewEncoder(out)
+	value := reflect.ValueOf(data)
+	if value.Kind() != reflect.Slice {
+		return encoder.Encode(data)
+	}
+	for i := 0; i < value.Len(); i++ {
+		if err := encoder.Encode(value.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateFuncs are available to custom templates.
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+}
+
+// IsTerminal reports whether f is an interactive terminal.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,197 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/s-Amine/token-scan/token"
+)
+
+// ANSI escape sequences used by the table format.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiRed     = "\x1b[31m"
+	ansiBoldRed = "\x1b[1;31m"
+	ansiYellow  = "\x1b[33m"
+	ansiGreen   = "\x1b[32m"
+	ansiCyan    = "\x1b[36m"
+)
+
+// headerStyle styles the column headers of the table format.
+const headerStyle = "header"
+
+// styleColors highlight severities and verdict levels.
+var styleColors = map[string]string{
+	headerStyle:                    ansiBold,
+	string(token.SeverityCritical): ansiBoldRed,
+	string(token.SeverityHigh):     ansiRed,
+	string(token.SeverityMedium):   ansiYellow,
+	string(token.SeverityLow):      ansiCyan,
+	token.VerdictDanger:            ansiBoldRed,
+	token.VerdictCaution:           ansiYellow,
+	token.VerdictSafe:              ansiGreen,
+	token.VerdictUnknown:           ansiCyan,
+}
+
+// escapePattern matches ANSI CSI and OSC escape sequences.
+var escapePattern = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(\x07|\x1b\\)?`)
+
+// cleanText strips escape sequences and control characters from text taken
+// from provider responses, so it cannot restyle or rewrite the terminal.
+// Line breaks and tabs become spaces.
+func cleanText(text string) string {
+	text = escapePattern.ReplaceAllString(text, "")
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\r' || r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, text)
+}
+
+// cleanTables returns tables with cleanText applied to every column and cell.
+func cleanTables(tables []Table) []Table {
+	cleaned := make([]Table, len(tables))
+	for i, table := range tables {
+		cleaned[i] = Table{Title: cleanText(table.Title), Columns: make([]string, len(table.Columns))}
+		for col, name := range table.Columns {
+			cleaned[i].Columns[col] = cleanText(name)
+		}
+		for _, row := range table.Rows {
+			cells := make([]Cell, len(row))
+			for col, cell := range row {
+				cells[col] = Cell{Text: cleanText(cell.Text), Style: cell.Style}
+			}
+			cleaned[i].Rows = append(cleaned[i].Rows, cells)
+		}
+	}
+	return cleaned
+}
+
+// numberPattern matches decimal numbers, which spreadsheets read as values.
+var numberPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// csvField protects a CSV field from formula injection: spreadsheets evaluate
+// fields starting with =, +, - or @, so these are prefixed with a quote unless
+// they are numbers, such as -1.5.
+func csvField(text string) string {
+	if text != "" && strings.ContainsRune("=+-@", rune(text[0])) && !numberPattern.MatchString(text) {
+		return "'" + text
+	}
+	return text
+}
+
+// renderTerminal writes tables as aligned columns, coloring styled cells
+// when color is set.
+func renderTerminal(out io.Writer, tables []Table, color bool) error {
+	var b strings.Builder
+	for i, table := range tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		title := strings.ToUpper(table.Title)
+		if color {
+			title = ansiBold + title + ansiReset
+		}
+		b.WriteString(title + "\n")
+
+		widths := make([]int, len(table.Columns))
+		for col, name := range table.Columns {
+			widths[col] = utf8.RuneCountInString(name)
+		}
+		for _, row := range table.Rows {
+			for col, cell := range row {
+				if n := utf8.RuneCountInString(cell.Text); n > widths[col] {
+					widths[col] = n
+				}
+			}
+		}
+
+		header := make([]Cell, len(table.Columns))
+		for col, name := range table.Columns {
+			header[col] = Cell{Text: name, Style: headerStyle}
+		}
+		for _, row := range append([][]Cell{header}, table.Rows...) {
+			for col, cell := range row {
+				if code, ok := styleColors[cell.Style]; ok && color {
+					b.WriteString(code + cell.Text + ansiReset)
+				} else {
+					b.WriteString(cell.Text)
+				}
+				if col < len(row)-1 {
+					b.WriteString(strings.Repeat(" ", widths[col]-utf8.RuneCountInString(cell.Text)+2))
+				}
+			}
+			b.WriteString("\n")
+		}
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// renderMarkdown writes tables as Markdown sections, emphasizing high and
+// critical severities and the danger verdict.
+func renderMarkdown(out io.Writer, tables []Table) error {
+	var b strings.Builder
+	for i, table := range tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "### %s\n\n", table.Title)
+		b.WriteString("| " + strings.Join(table.Columns, " | ") + " |\n")
+		b.WriteString("|" + strings.Repeat(" --- |", len(table.Columns)) + "\n")
+		for _, row := range table.Rows {
+			cells := make([]string, len(row))
+			for col, cell := range row {
+				text := strings.NewReplacer("|", `\|`, "\n", " ").Replace(cell.Text)
+				switch cell.Style {
+				case string(token.SeverityCritical), string(token.SeverityHigh), token.VerdictDanger:
+					text = "**" + text + "**"
+				}
+				cells[col] = text
+			}
+			b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+	}
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+// renderCSV writes each table as a CSV header and rows, separating tables
+// with an empty line.
+func renderCSV(out io.Writer, tables []Table) error {
+	for i, table := range tables {
+		if i > 0 {
+			if _, err := io.WriteString(out, "\n"); err != nil {
+				return err
+			}
+		}
+		writer := csv.NewWriter(out)
+		header := make([]string, len(table.Columns))
+		for col, name := range table.Columns {
+			header[col] = csvField(name)
+		}
+		writer.Write(header)
+		for _, row := range table.Rows {
+			record := make([]string, len(row))
+			for col, cell := range row {
+				record[col] = csvField(cell.Text)
+			}
+			writer.Write(record)
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
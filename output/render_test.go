@@ -0,0 +1,68 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCleanText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"PEPE", "PEPE"},
+		{"\x1b[31mRED\x1b[0m", "RED"},
+		{"\x1b]0;owned\x07Token", "Token"},
+		{"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "link"},
+		{"line\nbreak\ttab", "line break tab"},
+		{"bell\x07 and \x1b lone escape", "bell and  lone escape"},
+		{"🐸 Frog", "🐸 Frog"},
+	}
+	for _, tt := range tests {
+		if got := cleanText(tt.text); got != tt.want {
+			t.Errorf("cleanText(%q): got %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRenderCSVFormulas(t *testing.T) {
+	tables := []Table{{Title: "Fields", Columns: []string{"Field", "Value"}}}
+	for _, value := range []string{"=HYPERLINK(\"http://x\")", "+1", "-1.5", "-.5e3", "=1", "-1+A1", "-Inf", "+cmd|' /C calc'!A0", "@SUM(A1)", "plain"} {
+		tables[0].addRow("token_name", value)
+	}
+
+	var out bytes.Buffer
+	if err := renderCSV(&out, cleanTables(tables)); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"Field,Value",
+		`token_name,"'=HYPERLINK(""http://x"")"`,
+		"token_name,+1",
+		"token_name,-1.5",
+		"token_name,-.5e3",
+		"token_name,'=1",
+		"token_name,'-1+A1",
+		"token_name,'-Inf",
+		`token_name,'+cmd|' /C calc'!A0`,
+		"token_name,'@SUM(A1)",
+		"token_name,plain",
+	}, "\n") + "\n"
+	if got := out.String(); got != want {
+		t.Errorf("renderCSV: got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderTerminalStripsEscapes(t *testing.T) {
+	tables := []Table{{Title: "Fields", Columns: []string{"Field", "Value"}}}
+	tables[0].addRow("token_name", "\x1b[2J\x1b[HSAFE")
+
+	var out bytes.Buffer
+	if err := renderTerminal(&out, cleanTables(tables), false); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); strings.Contains(got, "\x1b") || !strings.Contains(got, "token_name  SAFE") {
+		t.Errorf("renderTerminal: got %q", got)
+	}
+}
@@ -0,0 +1,273 @@
+package output
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/s-Amine/token-scan/compare"
+	"github.com/s-Amine/token-scan/diff"
+	"github.com/s-Amine/token-scan/envelope"
+	"github.com/s-Amine/token-scan/history"
+	"github.com/s-Amine/token-scan/jsonfields"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/token"
+	"github.com/s-Amine/token-scan/watch"
+)
+
+// Table is a titled table of cells, the common form of the table, Markdown
+// and CSV formats.
+type Table struct {
+	Title   string
+	Columns []string
+	Rows    [][]Cell
+}
+
+// Cell is a table cell. Style is a severity or verdict level used to
+// highlight the cell, or empty.
+type Cell struct {
+	Text  string
+	Style string
+}
+
+// addRow appends a row of unstyled cells.
+func (t *Table) addRow(texts ...string) {
+	row := make([]Cell, len(texts))
+	for i, text := range texts {
+		row[i] = Cell{Text: text}
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+// tablesOf lays out a result as tables.
+func tablesOf(data interface{}) []Table {
+	switch v := data.(type) {
+	case *envelope.Envelope:
+		return envelopeTables(v)
+	case []*envelope.Envelope:
+		return []Table{envelopesTable(v)}
+	case *token.TokenInfo:
+		return scanTables(v)
+	case *multiscan.Result:
+		return resultTables(v)
+	case []history.Record:
+		return []Table{recordsTable(v)}
+	case *history.Record:
+		return recordTables(v)
+	case *diff.Report:
+		return reportTables(v)
+	case watch.Alert:
+		return alertTables(v)
+	case *compare.Comparison:
+		return []Table{comparisonTable(v)}
+	}
+	return []Table{fieldsTable("Fields", data, nil)}
+}
+
+// scanTables lays out unified token information: the verdict, the risk
+// factors and every other field.
+func scanTables(info *token.TokenInfo) []Table {
+	if info == nil {
+		return nil
+	}
+	var tables []Table
+
+	if info.Risk != nil {
+		verdict := Table{Title: "Verdict", Columns: []string{"Verdict", "Score"}}
+		verdict.Rows = append(verdict.Rows, []Cell{
+			{Text: info.Risk.Level, Style: info.Risk.Level},
+			{Text: fmt.Sprintf("%d/100", info.Risk.Score)},
+		})
+		tables = append(tables, verdict)
+
+		if len(info.Risk.Factors) > 0 {
+			factors := Table{Title: "Risk factors", Columns: []string{"Severity", "Factor", "Reason"}}
+			for _, factor := range info.Risk.Factors {
+				factors.Rows = append(factors.Rows, []Cell{
+					{Text: string(factor.Severity), Style: string(factor.Severity)},
+					{Text: factor.Code},
+					{Text: factor.Reason},
+				})
+			}
+			tables = append(tables, factors)
+		}
+	}
+
+	return append(tables, fieldsTable("Fields", info, map[string]bool{"risk": true}))
+}
+
+// resultTables lays out a multiscan result. Raw provider responses are left
+// to the JSON formats.
+func resultTables(result *multiscan.Result) []Table {
+	tables := scanTables(result.Unified)
+	if result.Raw != nil && len(result.Raw.Errors) > 0 {
+		errs := Table{Title: "Provider errors", Columns: []string{"Provider", "Error"}}
+		for _, provider := range jsonfields.SortedKeys(result.Raw.Errors) {
+			errs.addRow(provider, result.Raw.Errors[provider])
+		}
+		tables = append(tables, errs)
+	}
+	if len(result.DecodeProblems) > 0 {
+		problems := Table{Title: "Decode problems", Columns: []string{"Provider", "Field", "Value", "Reason"}}
+		for provider, list := range result.DecodeProblems {
+			for _, problem := range list {
+				problems.addRow(provider, problem.Field, problem.Value, problem.Reason)
+			}
+		}
+		sort.SliceStable(problems.Rows, func(i, j int) bool {
+			return problems.Rows[i][0].Text < problems.Rows[j][0].Text
+		})
+		tables = append(tables, problems)
+	}
+	return tables
+}
+
+// envelopeTables lays out an output envelope: what was scanned followed by
+// the tables of its result.
+func envelopeTables(e *envelope.Envelope) []Table {
+	scan := Table{Title: "Scan", Columns: []string{"Kind", "Chain", "Address", "Scanned at", "Sources"}}
+	scan.addRow(e.Kind, e.Chain.Name, e.Address, e.ScannedAt.Format("2006-01-02 15:04:05"), strings.Join(e.Sources, ", "))
+	return append([]Table{scan}, tablesOf(e.Result)...)
+}
+
+// envelopesTable lists the verdicts of several scans.
+func envelopesTable(envelopes []*envelope.Envelope) Table {
+	table := Table{Title: "Scans", Columns: []string{"Address", "Chain", "Verdict", "Score", "Sources"}}
+	for _, e := range envelopes {
+		var info *token.TokenInfo
+		switch result := e.Result.(type) {
+		case *token.TokenInfo:
+			info = result
+		case *multiscan.Result:
+			info = result.Unified
+		}
+		verdict, score := Cell{}, Cell{}
+		if info != nil && info.Risk != nil {
+			verdict = Cell{Text: info.Risk.Level, Style: info.Risk.Level}
+			score = Cell{Text: fmt.Sprintf("%d/100", info.Risk.Score)}
+		}
+		table.Rows = append(table.Rows, []Cell{
+			{Text: e.Address},
+			{Text: e.Chain.Name},
+			verdict,
+			score,
+			{Text: strings.Join(e.Sources, ", ")},
+		})
+	}
+	return table
+}
+
+// recordsTable lists history records.
+func recordsTable(records []history.Record) Table {
+	table := Table{Title: "Scans", Columns: []string{"ID", "Scanned at", "Chain", "Address", "Mode", "Verdict", "Flags"}}
+	for _, record := range records {
+		table.Rows = append(table.Rows, []Cell{
+			{Text: record.ID},
+			{Text: record.ScannedAt.Format("2006-01-02 15:04:05")},
+			{Text: record.Chain},
+			{Text: record.Address},
+			{Text: record.Mode},
+			{Text: record.Verdict, Style: record.Verdict},
+			{Text: strings.Join(record.Flags, ", ")},
+		})
+	}
+	return table
+}
+
+// recordTables lays out a history record and, for multiscans, its result.
+func recordTables(record *history.Record) []Table {
+	summary := *record
+	summary.Result = nil
+	tables := []Table{recordsTable([]history.Record{summary})}
+
+	if info, err := record.TokenInfo(); err == nil && info != nil {
+		return append(tables, scanTables(info)...)
+	}
+	return append(tables, fieldsTable("Result", record.Result, nil))
+}
+
+// reportTables lays out a diff report.
+func reportTables(report *diff.Report) []Table {
+	tables := []Table{changesTable("Changes", report.Changes)}
+	for _, provider := range jsonfields.SortedKeys(report.Raw) {
+		tables = append(tables, changesTable("Raw "+provider+" changes", report.Raw[provider]))
+	}
+	return tables
+}
+
+// alertTables lays out a watch alert.
+func alertTables(alert watch.Alert) []Table {
+	header := Table{Title: "Alert", Columns: []string{"Address", "Scanned at", "Severity", "Verdict"}}
+	header.Rows = append(header.Rows, []Cell{
+		{Text: alert.Address},
+		{Text: alert.ScannedAt.Format("2006-01-02 15:04:05")},
+		{Text: string(alert.Severity), Style: string(alert.Severity)},
+		{Text: alert.Verdict, Style: alert.Verdict},
+	})
+	return []Table{header, changesTable("Changes", alert.Changes)}
+}
+
+// comparisonTable lays out a token comparison with one column per token,
+// starting with their rank.
+func comparisonTable(comparison *compare.Comparison) Table {
+	table := Table{Title: "Comparison", Columns: []string{"Field"}}
+	rank := []Cell{{Text: "rank"}}
+	for _, candidate := range comparison.Candidates {
+		table.Columns = append(table.Columns, candidate.Address)
+		cell := Cell{Text: fmt.Sprint(candidate.Rank)}
+		if candidate.Address == comparison.MostLikelyLegitimate {
+			cell = Cell{Text: cell.Text + " (most likely legitimate)", Style: token.VerdictSafe}
+		}
+		if candidate.Incomplete {
+			cell.Text += " (incomplete scan)"
+		}
+		rank = append(rank, cell)
+	}
+	table.Rows = append(table.Rows, rank)
+
+	for _, row := range comparison.Rows {
+		cells := []Cell{{Text: row.Field}}
+		for _, value := range row.Values {
+			cell := Cell{Text: value}
+			if row.Field == "verdict" {
+				cell.Style = value
+			}
+			cells = append(cells, cell)
+		}
+		table.Rows = append(table.Rows, cells)
+	}
+	return table
+}
+
+// changesTable lists diff changes.
+func changesTable(title string, changes []diff.Change) Table {
+	table := Table{Title: title, Columns: []string{"Severity", "Field", "Old", "New"}}
+	for _, change := range changes {
+		table.Rows = append(table.Rows, []Cell{
+			{Text: string(change.Severity), Style: string(change.Severity)},
+			{Text: change.Field},
+			{Text: jsonfields.Format(change.Old)},
+			{Text: jsonfields.Format(change.New)},
+		})
+	}
+	return table
+}
+
+// fieldsTable lists the leaf fields of the JSON encoding of data in field
+// order, leaving out the top-level fields in skip. Arrays are shown as JSON.
+func fieldsTable(title string, data interface{}, skip map[string]bool) Table {
+	table := Table{Title: title, Columns: []string{"Field", "Value"}}
+
+	fields, err := jsonfields.Flatten(data)
+	if err != nil {
+		table.addRow("error", err.Error())
+		return table
+	}
+	for _, field := range jsonfields.SortedKeys(fields) {
+		if top, _, _ := strings.Cut(field, "."); skip[top] {
+			continue
+		}
+		table.addRow(field, jsonfields.Format(fields[field]))
+	}
+	return table
+}
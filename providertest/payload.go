@@ -0,0 +1,159 @@
+package providertest
+
+import (
+	"math/big"
+
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/models"
+	"github.com/s-Amine/token-scan/decode"
+	"github.com/s-Amine/token-scan/scanners/ishoneypot"
+	"github.com/s-Amine/token-scan/scanners/quickintel"
+)
+
+// Canned token metadata shared by every payload.
+const (
+	TokenName     = "Fake Token"
+	TokenSymbol   = "FAKE"
+	TokenDecimals = 18
+	PairAddress   = "0x00000000000000000000000000000000000000aa"
+	OwnerAddress  = "0x00000000000000000000000000000000000000bb"
+)
+
+// HoneypotPayload returns the honeypot.is response for address.
+func HoneypotPayload(address string, honeypot bool) ishoneypot.HoneypotResponse {
+	var r ishoneypot.HoneypotResponse
+	r.Token = ishoneypot.TokenInfo{
+		Name:         TokenName,
+		Symbol:       TokenSymbol,
+		Decimals:     TokenDecimals,
+		Address:      address,
+		TotalHolders: 1200,
+	}
+	r.WithToken = ishoneypot.TokenInfo{
+		Name:     "Wrapped Ether",
+		Symbol:   "WETH",
+		Decimals: 18,
+		Address:  "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+	}
+	r.Simulation = ishoneypot.Simulation{BuyGas: 120000, SellGas: 110000}
+	r.HoneypotResult.IsHoneypot = honeypot
+	r.ContractCode.OpenSource = !honeypot
+	r.ContractCode.RootOpenSource = !honeypot
+	r.Chain = ishoneypot.ChainInfo{ID: "1", Name: "Ethereum", ShortName: "ETH", Currency: "ETH"}
+	r.Router = "0x7a250d5630b4cf539739df2c5dacb4c659f2488d"
+	r.Pair = ishoneypot.PairInfo{
+		PairName:           "Uniswap V2: FAKE-WETH",
+		PairAddress:        PairAddress,
+		Token0:             address,
+		Token1:             r.WithToken.Address,
+		Type:               "UniswapV2",
+		ChainId:            "1",
+		Reserves0:          bigInt("1000000000000000000000000"),
+		Reserves1:          bigInt("50000000000000000000"),
+		Liquidity:          180000,
+		Router:             r.Router,
+		CreatedAtTimestamp: 1700000000,
+	}
+	r.PairAddress = PairAddress
+	r.HolderAnalysis.Holders = 1200
+	r.HolderAnalysis.Successful = 1200
+	r.HolderAnalysis.Failed = 0
+	r.HolderAnalysis.Siphoned = 0
+	r.HolderAnalysis.HighTaxWallets = 0
+	if honeypot {
+		r.Simulation.SellTax = 100
+		r.HolderAnalysis.Successful = 12
+		r.HolderAnalysis.Failed = 1188
+		r.HolderAnalysis.AverageTax = 99
+		r.HolderAnalysis.HighestTax = 100
+		r.HolderAnalysis.HighTaxWallets = 1188
+	}
+	return r
+}
+
+// QuickIntelPayload returns the QuickIntel audit for address.
+func QuickIntelPayload(address string, honeypot bool) quickintel.QuickIntelResponse {
+	var r quickintel.QuickIntelResponse
+	r.TokenDetails.TokenName = TokenName
+	r.TokenDetails.TokenSymbol = TokenSymbol
+	r.TokenDetails.TokenDecimals = TokenDecimals
+	r.TokenDetails.TokenOwner = OwnerAddress
+	r.TokenDetails.TokenSupply = bigInt("1000000000000000000000000000")
+	r.TokenDetails.TokenCreatedDate = 1700000000000
+	r.TokenDynamicDetails.LastUpdatedTimestamp = 1700000000000
+	r.TokenDynamicDetails.IsHoneypot = honeypot
+	r.QuickiAudit.ContractCreator = OwnerAddress
+	r.QuickiAudit.ContractOwner = "0x0000000000000000000000000000000000000000"
+	r.QuickiAudit.ContractName = "FakeToken"
+	r.QuickiAudit.ContractChain = "eth"
+	r.QuickiAudit.ContractAddress = address
+	r.QuickiAudit.ContractRenounced = !honeypot
+	if honeypot {
+		r.QuickiAudit.ContractOwner = OwnerAddress
+		r.QuickiAudit.HiddenOwner = true
+		r.QuickiAudit.CanBlacklist = true
+		r.QuickiAudit.CanUpdateFees = true
+		r.QuickiAudit.CanPauseTrading = true
+		r.QuickiAudit.HasModifiedTransferWarn = true
+		r.QuickiAudit.OnlyOwnerFunctions = []string{"setFees", "blacklist", "pause"}
+		r.QuickiAudit.HasScams = true
+		r.QuickiAudit.MatchedScams = "Honeypot Sell Block"
+		r.QuickiAudit.ScamFuncs = "_transfer"
+	}
+	return r
+}
+
+// GoPlusPayload returns the GoPlus token security response for address.
+func GoPlusPayload(address string, honeypot bool) models.ResponseWrapperTokenSecurity {
+	result := models.ResponseWrapperTokenSecurityResultAnon{
+		TokenName:      TokenName,
+		TokenSymbol:    TokenSymbol,
+		TotalSupply:    "1000000000",
+		HolderCount:    "1200",
+		CreatorAddress: OwnerAddress,
+		CreatorPercent: "0.02",
+		OwnerAddress:   "0x0000000000000000000000000000000000000000",
+		BuyTax:         "0",
+		SellTax:        "0",
+		IsOpenSource:   "1",
+		IsHoneypot:     "0",
+		IsInDex:        "1",
+		Dex: []*models.ResponseWrapperTokenSecurityResultAnonDexItems0{
+			{Name: "UniswapV2", Liquidity: "180000", Pair: PairAddress},
+		},
+		Holders: []*models.ResponseWrapperTokenSecurityResultAnonHoldersItems0{
+			{Address: PairAddress, Tag: "UniswapV2", Percent: "0.25", IsContract: 1},
+			{Address: "0x000000000000000000000000000000000000dead", Percent: "0.2"},
+			{Address: OwnerAddress, Percent: "0.02"},
+		},
+		LpHolderCount: "2",
+		LpHolders: []*models.ResponseWrapperTokenSecurityResultAnonLpHoldersItems0{
+			{Address: "0x000000000000000000000000000000000000dead", Percent: "0.95"},
+			{Address: OwnerAddress, Percent: "0.05"},
+		},
+	}
+	if honeypot {
+		result.OwnerAddress = OwnerAddress
+		result.OwnerPercent = "0.4"
+		result.Holders[2].Percent = "0.4"
+		result.LpHolders[0].Percent = "0.05"
+		result.LpHolders[1].Percent = "0.95"
+		result.SellTax = "1"
+		result.IsOpenSource = "0"
+		result.IsHoneypot = "1"
+		result.CannotSellAll = "1"
+		result.HiddenOwner = "1"
+		result.IsBlacklisted = "1"
+		result.TransferPausable = "1"
+	}
+	return models.ResponseWrapperTokenSecurity{
+		Code:    1,
+		Message: "OK",
+		Result:  map[string]models.ResponseWrapperTokenSecurityResultAnon{address: result},
+	}
+}
+
+// bigInt parses a base 10 integer constant.
+func bigInt(value string) decode.BigInt {
+	n, _ := new(big.Int).SetString(value, 10)
+	return decode.NewBigInt(n)
+}
@@ -0,0 +1,208 @@
+package providertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Behavior selects the canned response served for an address.
+type Behavior string
+
+// Canned behaviors supported by Server.
+const (
+	// BehaviorClean serves a legitimate, sellable token.
+	BehaviorClean Behavior = "clean"
+	// BehaviorHoneypot serves a token every provider flags as a honeypot.
+	BehaviorHoneypot Behavior = "honeypot"
+	// BehaviorError answers with an HTTP 500.
+	BehaviorError Behavior = "error"
+	// BehaviorSlow serves the clean payload after SlowDelay.
+	BehaviorSlow Behavior = "slow"
+)
+
+// Provider request paths served by Server.
+const (
+	HoneypotPath       = "/v2/IsHoneypot"
+	QuickIntelPath     = "/api/quicki/getquickiauditfull"
+	GoPlusSecurityPath = "/api/v1/token_security/"
+	GoPlusAccessPath   = "/api/v1/token"
+)
+
+const (
+	// defaultSlowDelay is the initial value of Server.SlowDelay.
+	defaultSlowDelay = 2 * time.Second
+	// accessTokenLifetime is the lifetime in seconds of issued GoPlus access tokens.
+	accessTokenLifetime = 3600
+)
+
+// Server is an in-process stand-in for the honeypot.is, QuickIntel and GoPlus
+// endpoints used by the scanners. Responses are scripted per token address.
+type Server struct {
+	*httptest.Server
+
+	// Default is the behavior for addresses that were not scripted.
+	Default Behavior
+	// SlowDelay is how long BehaviorSlow waits before answering.
+	SlowDelay time.Duration
+
+	mu        sync.Mutex
+	behaviors map[string]Behavior
+	requests  []receivedRequest
+}
+
+// receivedRequest is a request received by the server with its body, read
+// before the handler consumed it.
+type receivedRequest struct {
+	request *http.Request
+	body    []byte
+}
+
+// NewServer starts a Server serving BehaviorClean for every address.
+// The caller must Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		Default:   BehaviorClean,
+		SlowDelay: defaultSlowDelay,
+		behaviors: make(map[string]Behavior),
+	}
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// Script sets the behavior served for address.
+func (s *Server) Script(address string, behavior Behavior) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.behaviors[strings.ToLower(address)] = behavior
+}
+
+// Requests returns the requests received so far. Each call returns fresh
+// copies whose Body can be read, such as the QuickIntel POST payloads.
+func (s *Server) Requests() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	requests := make([]*http.Request, len(s.requests))
+	for i, received := range s.requests {
+		req := received.request.Clone(received.request.Context())
+		req.Body = io.NopCloser(bytes.NewReader(received.body))
+		req.ContentLength = int64(len(received.body))
+		requests[i] = req
+	}
+	return requests
+}
+
+// Transport returns a round tripper sending every request to the server,
+// whatever its original host. Install it with transport.SetRoundTripper to
+// point all scanners at the fakes.
+func (s *Server) Transport() http.RoundTripper {
+	target, _ := url.Parse(s.URL)
+	return &redirectTransport{target: target, next: s.Client().Transport}
+}
+
+// behavior returns the behavior scripted for address.
+func (s *Server) behavior(address string) Behavior {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if behavior, ok := s.behaviors[strings.ToLower(address)]; ok {
+		return behavior
+	}
+	return s.Default
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "error reading request body", http.StatusBadRequest)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, receivedRequest{request: r.Clone(r.Context()), body: body})
+	s.mu.Unlock()
+
+	switch {
+	case r.URL.Path == HoneypotPath && r.Method == http.MethodGet:
+		address := r.URL.Query().Get("address")
+		s.respond(w, r, address, func(honeypot bool) interface{} {
+			return HoneypotPayload(address, honeypot)
+		})
+	case r.URL.Path == QuickIntelPath && r.Method == http.MethodPost:
+		var body struct {
+			TokenAddress string `json:"tokenAddress"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		s.respond(w, r, body.TokenAddress, func(honeypot bool) interface{} {
+			return QuickIntelPayload(body.TokenAddress, honeypot)
+		})
+	case strings.HasPrefix(r.URL.Path, GoPlusSecurityPath) && r.Method == http.MethodGet:
+		address := r.URL.Query().Get("contract_addresses")
+		s.respond(w, r, address, func(honeypot bool) interface{} {
+			return GoPlusPayload(address, honeypot)
+		})
+	case r.URL.Path == GoPlusAccessPath && r.Method == http.MethodPost:
+		writeJSON(w, map[string]interface{}{
+			"code":    1,
+			"message": "OK",
+			"result": map[string]interface{}{
+				"access_token": "fake-access-token",
+				"expires_in":   accessTokenLifetime,
+			},
+		})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// respond writes the payload matching the behavior scripted for address.
+func (s *Server) respond(w http.ResponseWriter, r *http.Request, address string, payload func(honeypot bool) interface{}) {
+	switch s.behavior(address) {
+	case BehaviorError:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+	case BehaviorHoneypot:
+		writeJSON(w, payload(true))
+	case BehaviorSlow:
+		select {
+		case <-time.After(s.SlowDelay):
+			writeJSON(w, payload(false))
+		case <-r.Context().Done():
+		}
+	default:
+		writeJSON(w, payload(false))
+	}
+}
+
+// writeJSON writes v as a JSON response.
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// redirectTransport rewrites request URLs to target before sending them.
+type redirectTransport struct {
+	target *url.URL
+	next   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	redirected := req.Clone(req.Context())
+	redirected.URL.Scheme = t.target.Scheme
+	redirected.URL.Host = t.target.Host
+	redirected.Host = t.target.Host
+	return t.next.RoundTrip(redirected)
+}
@@ -0,0 +1,316 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/token"
+)
+
+// Token is a scanned token to report on. Result should carry the raw
+// provider responses, which feed the provider comparison and raw sections.
+type Token struct {
+	Address string
+	Chain   chain.Chain
+	Result  *multiscan.Result
+}
+
+// comparedFields are the unified fields compared across providers, in
+// display order.
+var comparedFields = []string{
+	"token_name", "token_symbol", "decimals", "uniswapv2_pair",
+	"is_honeypot", "cannot_buy", "cannot_sell_all", "buy_tax", "sell_tax",
+	"is_open_source", "hidden_owner", "can_take_back_ownership", "owner_change_balance",
+	"is_mintable", "transfer_pausable", "is_blacklisted", "is_whitelisted", "can_whitelist",
+	"external_call", "trading_cooldown", "personal_slippage_modifiable",
+}
+
+// providers are the compared providers, in column order.
+var providers = []struct {
+	name   string
+	source string
+}{
+	{"GoPlus", token.SourceGoPlus},
+	{"honeypot.is", token.SourceHoneypot},
+	{"QuickIntel", token.SourceQuickIntel},
+}
+
+// Write renders a self-contained HTML report of tokens to w.
+func Write(w io.Writer, tokens []Token, generated time.Time) error {
+	view := reportView{Generated: generated.UTC().Format("2006-01-02 15:04 MST")}
+	for _, t := range tokens {
+		tv, err := newTokenView(t)
+		if err != nil {
+			return err
+		}
+		view.Tokens = append(view.Tokens, tv)
+	}
+	for _, p := range providers {
+		view.Providers = append(view.Providers, p.name)
+	}
+	return reportTemplate.Execute(w, view)
+}
+
+// reportView is the data of the report template.
+type reportView struct {
+	Generated string
+	Providers []string
+	Tokens    []tokenView
+}
+
+// tokenView is the data of one token section.
+type tokenView struct {
+	Address    string
+	Chain      string
+	Name       string
+	Level      string
+	Score      int
+	Factors    []token.RiskFactor
+	Comparison []comparisonRow
+	Liquidity  []detail
+	Holders    []detail
+	Raw        []rawSection
+	Errors     []detail
+}
+
+// comparisonRow compares one unified field across providers.
+type comparisonRow struct {
+	Field     string
+	Meaning   string
+	Values    []string
+	Unified   string
+	Disagrees bool
+}
+
+// detail is a labelled value.
+type detail struct {
+	Label string
+	Value string
+}
+
+// rawSection is the raw response of a provider.
+type rawSection struct {
+	Provider string
+	JSON     string
+}
+
+// newTokenView builds the section of a token.
+func newTokenView(t Token) (tokenView, error) {
+	info := t.Result.Unified
+	tv := tokenView{
+		Address: t.Address,
+		Chain:   t.Chain.Name,
+		Name:    tokenName(info),
+		Level:   "unknown",
+	}
+	if info.Risk != nil {
+		tv.Level = info.Risk.Level
+		tv.Score = info.Risk.Score
+		tv.Factors = append(tv.Factors, info.Risk.Factors...)
+		sort.SliceStable(tv.Factors, func(i, j int) bool {
+			return tv.Factors[i].Severity.Rank() > tv.Factors[j].Severity.Rank()
+		})
+	}
+
+	tv.Comparison = compareProviders(t.Result)
+	tv.Liquidity = liquidityDetails(info)
+	tv.Holders = holderDetails(info)
+
+	if raw := t.Result.Raw; raw != nil {
+		sections := []struct {
+			provider string
+			response interface{}
+			present  bool
+		}{
+			{"GoPlus", raw.GoPlus, raw.GoPlus != nil},
+			{"honeypot.is", raw.Honeypot, raw.Honeypot != nil},
+			{"QuickIntel", raw.QuickIntel, raw.QuickIntel != nil},
+		}
+		for _, section := range sections {
+			if !section.present {
+				continue
+			}
+			data, err := json.MarshalIndent(section.response, "", "  ")
+			if err != nil {
+				return tv, fmt.Errorf("error marshaling %s response: %v", section.provider, err)
+			}
+			tv.Raw = append(tv.Raw, rawSection{Provider: section.provider, JSON: string(data)})
+		}
+		for provider, msg := range raw.Errors {
+			tv.Errors = append(tv.Errors, detail{Label: provider, Value: msg})
+		}
+		sort.Slice(tv.Errors, func(i, j int) bool { return tv.Errors[i].Label < tv.Errors[j].Label })
+	}
+	return tv, nil
+}
+
+// compareProviders lays out the unified fields as mapped from each provider
+// response. A provider that does not map a field, or whose response is
+// missing, shows a dash.
+func compareProviders(result *multiscan.Result) []comparisonRow {
+	perProvider := map[string]map[string]interface{}{}
+	if raw := result.Raw; raw != nil {
+		if raw.GoPlus != nil {
+			perProvider[token.SourceGoPlus] = fieldValues(token.InitTokenInfoFromGoPlus(*raw.GoPlus))
+		}
+		if raw.Honeypot != nil {
+			perProvider[token.SourceHoneypot] = fieldValues(token.InitTokenInfoFromHoneypotResponse(*raw.Honeypot, result.ScannedAt))
+		}
+		if raw.QuickIntel != nil {
+			perProvider[token.SourceQuickIntel] = fieldValues(token.InitTokenInfoFromQuickIntelResponse(*raw.QuickIntel))
+		}
+	}
+
+	mapped := map[string]bool{}
+	for _, m := range token.FieldMappings() {
+		mapped[m.Provider+"."+m.Field] = true
+	}
+
+	unified := fieldValues(result.Unified)
+	rows := make([]comparisonRow, 0, len(comparedFields))
+	for _, field := range comparedFields {
+		row := comparisonRow{Field: field, Meaning: token.FieldSemantics[field], Unified: formatField(unified, field)}
+		seen := map[string]bool{}
+		for _, p := range providers {
+			values, ok := perProvider[p.source]
+			if !ok || !mapped[p.source+"."+field] {
+				row.Values = append(row.Values, "—")
+				continue
+			}
+			value := formatField(values, field)
+			row.Values = append(row.Values, value)
+			seen[value] = true
+		}
+		row.Disagrees = len(seen) > 1
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// fieldValues returns the top-level JSON fields of info.
+func fieldValues(info *token.TokenInfo) map[string]interface{} {
+	values := map[string]interface{}{}
+	data, err := json.Marshal(info)
+	if err == nil {
+		json.Unmarshal(data, &values)
+	}
+	return values
+}
+
+// textFields are the compared fields that are not booleans.
+var textFields = map[string]bool{
+	"token_name": true, "token_symbol": true, "decimals": true,
+	"uniswapv2_pair": true, "buy_tax": true, "sell_tax": true,
+}
+
+// formatField formats a field for display. Booleans left out of the JSON
+// because they are false show as "no", other empty fields as a dash.
+func formatField(values map[string]interface{}, field string) string {
+	switch v := values[field].(type) {
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		if v != "" {
+			return v
+		}
+	case nil:
+		if !textFields[field] {
+			return "no"
+		}
+	}
+	return "—"
+}
+
+// tokenName returns the display name of a token.
+func tokenName(info *token.TokenInfo) string {
+	switch {
+	case info.TokenName != "" && info.TokenSymbol != "":
+		return fmt.Sprintf("%s (%s)", info.TokenName, info.TokenSymbol)
+	case info.TokenName != "":
+		return info.TokenName
+	case info.TokenSymbol != "":
+		return info.TokenSymbol
+	}
+	return "Unknown token"
+}
+
+// liquidityDetails lists the liquidity section.
+func liquidityDetails(info *token.TokenInfo) []detail {
+	l := info.Liquidity
+	if l == nil {
+		return nil
+	}
+	details := []detail{
+		{"Pair", l.PairAddress},
+		{"Pair name", l.PairName},
+		{"DEX", l.Dex},
+		{"Router", l.Router},
+		{"Quote token", strings.TrimSpace(l.QuoteSymbol + " " + l.QuoteToken)},
+		{"Liquidity", "$" + strconv.FormatFloat(l.LiquidityUSD, 'f', 2, 64)},
+	}
+	if l.TokenReserve != nil {
+		details = append(details, detail{"Token reserve", l.TokenReserve.String()})
+	}
+	if l.QuoteReserve != nil {
+		details = append(details, detail{"Quote reserve", l.QuoteReserve.String()})
+	}
+	if l.CreatedAt != nil {
+		details = append(details, detail{"Created", l.CreatedAt.UTC().Format("2006-01-02 15:04 MST")})
+		details = append(details, detail{"Age", fmt.Sprintf("%.1f days", l.AgeHours/24)})
+	}
+	return nonEmpty(details)
+}
+
+// holderDetails lists the holder analysis and concentration sections.
+func holderDetails(info *token.TokenInfo) []detail {
+	var details []detail
+	if h := info.HolderAnalysis; h != nil {
+		details = append(details,
+			detail{"Holders simulated", strconv.Itoa(h.Holders)},
+			detail{"Able to sell", strconv.Itoa(h.Successful)},
+			detail{"Unable to sell", fmt.Sprintf("%d (%s)", h.Failed, percent(h.FailedSellShare))},
+			detail{"Siphoned wallets", strconv.Itoa(h.Siphoned)},
+			detail{"Average tax", strconv.FormatFloat(h.AverageTax, 'f', -1, 64) + "%"},
+			detail{"Highest tax", strconv.FormatFloat(h.HighestTax, 'f', -1, 64) + "%"},
+			detail{"Snipers", fmt.Sprintf("%d succeeded, %d failed", h.SnipersSuccess, h.SnipersFailed)},
+		)
+	}
+	if c := info.Concentration; c != nil {
+		details = append(details,
+			detail{"Top 10 holders", percent(c.TopHoldersShare)},
+			detail{"Creator share", percent(c.CreatorShare)},
+			detail{"Owner share", percent(c.OwnerShare)},
+			detail{"LP locked", percent(c.LPLockedShare)},
+			detail{"LP burned", percent(c.LPBurnedShare)},
+		)
+	}
+	return details
+}
+
+// percent formats a fraction as a percentage.
+func percent(fraction float64) string {
+	return strconv.FormatFloat(fraction*100, 'f', -1, 64) + "%"
+}
+
+// nonEmpty drops details without a value.
+func nonEmpty(details []detail) []detail {
+	kept := details[:0]
+	for _, d := range details {
+		if d.Value != "" {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
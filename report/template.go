@@ -0,0 +1,92 @@
+package report
+
+import (
+	"html/template"
+	"strings"
+)
+
+// reportTemplate renders the report. Styles are inlined so that the page has
+// no external assets.
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"upper": strings.ToUpper,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Token scan report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif; margin: 0 auto; max-width: 1100px; padding: 24px; color: #1f2328; background: #fff; }
+h1 { margin-bottom: 4px; }
+h3 { margin: 24px 0 8px; }
+.meta { color: #656d76; margin-top: 0; }
+section.token { border: 1px solid #d0d7de; border-radius: 8px; padding: 0 20px 20px; margin: 24px 0; overflow: hidden; }
+.banner { margin: 0 -20px; padding: 16px 20px; color: #fff; }
+.banner .level { font-size: 1.6em; font-weight: 700; margin-right: 12px; }
+.banner code { color: #fff; opacity: 0.9; }
+.banner.safe { background: #1a7f37; }
+.banner.caution { background: #bf8700; }
+.banner.danger { background: #cf222e; }
+.banner.unknown { background: #656d76; }
+table { border-collapse: collapse; width: 100%; font-size: 0.92em; }
+th, td { border-bottom: 1px solid #d8dee4; padding: 6px 8px; text-align: left; vertical-align: top; }
+th { background: #f6f8fa; }
+tr.disagree td { background: #fff8c5; }
+.severity { font-weight: 600; text-transform: uppercase; font-size: 0.85em; }
+.severity.critical { color: #a40e26; }
+.severity.high { color: #cf222e; }
+.severity.medium { color: #9a6700; }
+.severity.low { color: #0969da; }
+dl { display: grid; grid-template-columns: max-content 1fr; gap: 4px 16px; margin: 0; }
+dt { color: #656d76; }
+dd { margin: 0; word-break: break-all; }
+details { margin: 6px 0; }
+summary { cursor: pointer; font-weight: 600; }
+pre { background: #f6f8fa; padding: 12px; overflow-x: auto; font-size: 0.85em; }
+.muted { color: #656d76; }
+</style>
+</head>
+<body>
+<h1>Token scan report</h1>
+<p class="meta">Generated {{.Generated}} · {{len .Tokens}} token{{if ne (len .Tokens) 1}}s{{end}}</p>
+{{range .Tokens}}
+<section class="token">
+<div class="banner {{.Level}}">
+<span class="level">{{upper .Level}}</span>score {{.Score}}/100 · {{.Name}} on {{.Chain}}<br>
+<code>{{.Address}}</code>
+</div>
+
+<h3>Risk factors</h3>
+{{if .Factors}}
+<table>
+<tr><th>Severity</th><th>Factor</th><th>Reason</th></tr>
+{{range .Factors}}<tr><td class="severity {{.Severity}}">{{.Severity}}</td><td>{{.Code}}</td><td>{{.Reason}}</td></tr>
+{{end}}</table>
+{{else}}<p class="muted">No risk factors.</p>{{end}}
+
+<h3>Provider comparison</h3>
+<table>
+<tr><th>Field</th>{{range $.Providers}}<th>{{.}}</th>{{end}}<th>Unified</th></tr>
+{{range .Comparison}}<tr{{if .Disagrees}} class="disagree"{{end}}><td title="{{.Meaning}}">{{.Field}}</td>{{range .Values}}<td>{{.}}</td>{{end}}<td>{{.Unified}}</td></tr>
+{{end}}</table>
+<p class="muted">Highlighted rows are fields the providers disagree on. A dash marks a field the provider does not report.</p>
+
+<h3>Liquidity</h3>
+{{if .Liquidity}}<dl>{{range .Liquidity}}<dt>{{.Label}}</dt><dd>{{.Value}}</dd>{{end}}</dl>
+{{else}}<p class="muted">No liquidity information.</p>{{end}}
+
+<h3>Holders</h3>
+{{if .Holders}}<dl>{{range .Holders}}<dt>{{.Label}}</dt><dd>{{.Value}}</dd>{{end}}</dl>
+{{else}}<p class="muted">No holder information.</p>{{end}}
+
+{{if .Errors}}<h3>Provider errors</h3>
+<dl>{{range .Errors}}<dt>{{.Label}}</dt><dd>{{.Value}}</dd>{{end}}</dl>
+{{end}}
+{{if .Raw}}<h3>Raw provider responses</h3>
+{{range .Raw}}<details><summary>{{.Provider}}</summary><pre>{{.JSON}}</pre></details>
+{{end}}{{end}}
+</section>
+{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/s-Amine/token-scan/report"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+)
+
+// reportCommand implements the report command. It scans tokens and renders a
+// self-contained HTML report of them.
+func reportCommand(flags *flag.FlagSet) func(args []string) {
+	tokens := flags.String("tokens", "", "Comma-separated token hashes to report on")
+	outPath := flags.String("out", "", "File to write the report to (defaults to stdout)")
+	noHistory := flags.Bool("no-history", false, "Do not record the scans in the history store")
+
+	return func(args []string) {
+		addresses, _ := tokenList(*tokens, "")
+		addresses = append(addresses, args...)
+		if len(addresses) == 0 {
+			usageError(flags, "-tokens or token hash arguments are required")
+		}
+		c := globals.scanChain
+		cfg := loadConfig()
+
+		var reported []report.Token
+		for _, address := range addresses {
+			result := multiscan.ScanWithOptions(address, multiscan.Options{Chain: c, IncludeRaw: true})
+			reportProviderErrors(address, result)
+			if !*noHistory && !cfg.History.Disabled {
+				recordHistory(cfg.History.Path, "multiscan", c, address, result)
+			}
+			reported = append(reported, report.Token{Address: address, Chain: c, Result: result})
+		}
+
+		var out io.Writer = os.Stdout
+		if *outPath != "" {
+			file, err := os.Create(*outPath)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			defer file.Close()
+			out = file
+		}
+		if err := report.Write(out, reported, time.Now()); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		if *outPath != "" {
+			infof("Report of %s written to %s", strings.Join(addresses, ", "), *outPath)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/s-Amine/token-scan/decode"
+	"github.com/s-Amine/token-scan/envelope"
+	"github.com/s-Amine/token-scan/scanners/goplus"
+	"github.com/s-Amine/token-scan/scanners/ishoneypot"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/scanners/quickintel"
+	"github.com/s-Amine/token-scan/transport"
+)
+
+// scanModes lists the values of the scan -mode flag.
+var scanModes = []string{"multiscan", "goplus", "ishoneypot", "quickIntel"}
+
+// scanCommand implements the scan command. It scans a token and prints the
+// result in an output envelope.
+func scanCommand(flags *flag.FlagSet) func(args []string) {
+	mode := flags.String("mode", "multiscan", "Providers to scan with: multiscan, goplus, ishoneypot or quickIntel")
+	includeRaw := flags.Bool("include-raw", false, "Attach each provider's full response to the multiscan output")
+	strict := flags.Bool("strict", false, "Report provider response fields that are unknown or missing on stderr")
+	noHistory := flags.Bool("no-history", false, "Do not record the scan in the history store")
+	useFixtures := addFixtureFlags(flags)
+
+	return func(args []string) {
+		if len(args) != 1 {
+			usageError(flags, "one token hash is required")
+		}
+		tokenHash := args[0]
+		c := globals.scanChain
+
+		cfg := loadConfig()
+		useFixtures()
+		if *strict {
+			decode.EnableDriftTracking(true)
+		}
+
+		// result is the full scan recorded in the history, which may differ
+		// from the printed envelope result
+		var result interface{}
+		var wrapped *envelope.Envelope
+		var kind string
+		var err error
+
+		switch *mode {
+		case "multiscan":
+			// Raw responses tell which providers failed, even when not printed
+			scan := multiscan.ScanWithOptions(tokenHash, multiscan.Options{IncludeRaw: true, Chain: c})
+			reportProviderErrors(tokenHash, scan)
+			wrapped = envelope.Multiscan(c, tokenHash, scan, *includeRaw)
+			result = scan
+		case "goplus":
+			result, err = goplus.ScanChain(c, tokenHash)
+			kind = envelope.KindGoPlus
+		case "ishoneypot":
+			result, err = ishoneypot.ScanChain(c, tokenHash)
+			kind = envelope.KindHoneypot
+		case "quickIntel":
+			result, err = quickintel.ScanChain(c, tokenHash)
+			kind = envelope.KindQuickIntel
+		default:
+			usageError(flags, "invalid mode %q", *mode)
+		}
+
+		if err != nil {
+			fmt.Printf("Error occurred during %s scan: %v\n", *mode, err)
+			os.Exit(1)
+		}
+
+		if wrapped == nil {
+			wrapped = envelope.New(kind, c, tokenHash, []string{kind}, result)
+		}
+		printResult(wrapped)
+
+		if !*noHistory && !cfg.History.Disabled {
+			recordHistory(cfg.History.Path, *mode, c, tokenHash, result)
+		}
+
+		if *strict {
+			printDrift()
+		}
+	}
+}
+
+// addFixtureFlags registers the -record and -replay flags on flags and
+// returns the function installing the selected transport.
+func addFixtureFlags(flags *flag.FlagSet) func() {
+	recordDir := flags.String("record", "", "Directory to save provider responses to as fixtures")
+	replayDir := flags.String("replay", "", "Directory of fixtures to serve provider responses from, without network access")
+
+	return func() {
+		if *recordDir != "" && *replayDir != "" {
+			fmt.Println("Error: -record and -replay cannot be used together")
+			os.Exit(1)
+		}
+		if *recordDir != "" {
+			transport.SetRoundTripper(transport.NewRecorder(*recordDir, nil))
+		}
+		if *replayDir != "" {
+			replayer, err := transport.NewReplayer(*replayDir)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			transport.SetRoundTripper(replayer)
+		}
+	}
+}
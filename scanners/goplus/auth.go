@@ -0,0 +1,79 @@
+package goplus
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/errorcode"
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/client"
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/client/token_controller"
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/models"
+	"github.com/s-Amine/token-scan/transport"
+)
+
+// refreshMargin is how long before expiry a cached access token is renewed.
+const refreshMargin = 5 * time.Minute
+
+// accessTokenCache obtains GoPlus access tokens from an app key/secret pair
+// and keeps the current one until it is about to expire.
+type accessTokenCache struct {
+	appKey    string
+	appSecret string
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// get returns a valid access token, requesting a new one when the cached
+// token is missing or expires within refreshMargin.
+func (c *accessTokenCache) get(now time.Time) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token != "" && now.Add(refreshMargin).Before(c.expires) {
+		return c.token, nil
+	}
+
+	token, expiresIn, err := requestAccessToken(c.appKey, c.appSecret, now)
+	if err != nil {
+		return "", err
+	}
+	c.token = token
+	c.expires = now.Add(time.Duration(expiresIn) * time.Second)
+
+	return c.token, nil
+}
+
+// requestAccessToken exchanges an app key/secret pair for an access token.
+// The request is signed with sha1(appKey + time + appSecret) as required by GoPlus.
+func requestAccessToken(appKey, appSecret string, now time.Time) (string, int64, error) {
+	timestamp := now.Unix()
+	digest := sha1.Sum([]byte(appKey + strconv.FormatInt(timestamp, 10) + appSecret))
+	sign := hex.EncodeToString(digest[:])
+
+	params := token_controller.NewGetAccessTokenUsingPOSTParams()
+	params.SetHTTPClient(transport.Client(0))
+	params.SetRequest(&models.GetAccessTokenRequest{
+		AppKey: &appKey,
+		Sign:   &sign,
+		Time:   &timestamp,
+	})
+
+	ok, _, err := client.Default.TokenController.GetAccessTokenUsingPOST(params)
+	if err != nil {
+		return "", 0, fmt.Errorf("error requesting GoPlus access token: %v", err)
+	}
+	if ok == nil || ok.Payload == nil {
+		return "", 0, fmt.Errorf("error requesting GoPlus access token: empty response")
+	}
+	if ok.Payload.Code != errorcode.SUCCESS || ok.Payload.Result == nil {
+		return "", 0, fmt.Errorf("error requesting GoPlus access token: %s", ok.Payload.Message)
+	}
+
+	return ok.Payload.Result.AccessToken, ok.Payload.Result.ExpiresIn, nil
+}
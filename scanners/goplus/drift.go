@@ -0,0 +1,53 @@
+package goplus
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/models"
+	"github.com/s-Amine/token-scan/decode"
+	"github.com/s-Amine/token-scan/transport"
+)
+
+// driftTransport inspects token security responses for schema drift before
+// handing them to the SDK, which silently drops unknown fields.
+type driftTransport struct {
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *driftTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	if err != nil || res.StatusCode != http.StatusOK {
+		return res, err
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	var response models.ResponseWrapperTokenSecurity
+	if report, err := decode.DecodeReport(body, &response); err == nil {
+		decode.RecordDrift(Name, report)
+	}
+
+	return res, nil
+}
+
+// httpClient returns the HTTP client for token security requests, inspecting
+// responses for schema drift when tracking is enabled.
+func httpClient() *http.Client {
+	client := transport.Client(0)
+	if decode.DriftTrackingEnabled() {
+		next := client.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		client.Transport = &driftTransport{next: next}
+	}
+	return client
+}
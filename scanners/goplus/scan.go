@@ -2,23 +2,92 @@ package goplus
 
 import (
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/GoPlusSecurity/goplus-sdk-go/api/token"
 	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/errorcode"
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/client"
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/client/token_controller_v_1"
 	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/models"
+	"github.com/s-Amine/token-scan/chain"
 )
 
+// Name identifies the provider in drift statistics and diagnostics.
+const Name = "goplus"
+
+// Config holds the GoPlus credentials and request settings.
+// Leaving AppKey and AppSecret empty keeps the anonymous rate limit.
+type Config struct {
+	AppKey    string `json:"app_key,omitempty"`
+	AppSecret string `json:"app_secret,omitempty"`
+	// Timeout is the request timeout in seconds.
+	Timeout int `json:"timeout,omitempty"`
+}
+
+var (
+	configMu sync.RWMutex
+	config   Config
+	tokens   *accessTokenCache
+)
+
+// Configure sets the configuration used by subsequent scans.
+// Any cached access token is discarded.
+func Configure(cfg Config) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	config = cfg
+	tokens = nil
+	if cfg.AppKey != "" && cfg.AppSecret != "" {
+		tokens = &accessTokenCache{appKey: cfg.AppKey, appSecret: cfg.AppSecret}
+	}
+}
+
+// securityParams builds the token security request parameters, obtaining an
+// access token when credentials are configured.
+func securityParams(chainId string, contractAddresses []string) (*token_controller_v_1.TokenSecurityUsingGET1Params, error) {
+	configMu.RLock()
+	cfg, cache := config, tokens
+	configMu.RUnlock()
+
+	params := token_controller_v_1.NewTokenSecurityUsingGET1Params()
+	params.SetChainID(chainId)
+	params.SetContractAddresses(strings.Join(contractAddresses, ","))
+	params.SetHTTPClient(httpClient())
+	if cfg.Timeout != 0 {
+		params.SetTimeout(time.Duration(cfg.Timeout) * time.Second)
+	}
+	if cache != nil {
+		accessToken, err := cache.get(time.Now())
+		if err != nil {
+			return nil, err
+		}
+		params.SetAuthorization(&accessToken)
+	}
+
+	return params, nil
+}
+
 // Scan performs a security scan on a token identified by its hash.
 // It returns the security result wrapped in a response structure.
 func Scan(tokenHash string) (models.ResponseWrapperTokenSecurityResultAnon, error) {
-	// Create a new token security instance
-	tokenSecurity := token.NewTokenSecurity(nil)
+	return ScanChain(chain.Default, tokenHash)
+}
+
+// ScanChain performs a security scan on a token of the given chain.
+func ScanChain(c chain.Chain, tokenHash string) (models.ResponseWrapperTokenSecurityResultAnon, error) {
 	// Specify the chain ID
-	chainId := "1"
+	chainId := c.ID
 	// Prepare the list of contract addresses for scanning
 	contractAddresses := []string{tokenHash}
+	// Resolve credentials, timeout and HTTP client
+	params, err := securityParams(chainId, contractAddresses)
+	if err != nil {
+		return models.ResponseWrapperTokenSecurityResultAnon{}, err
+	}
 	// Run the security scan
-	data, err := tokenSecurity.Run(chainId, contractAddresses)
+	data, err := client.Default.TokenControllerv1.TokenSecurityUsingGET1(params)
 	// Handle any errors that occur during the scan
 	if err != nil {
 		// Return the error if it exists
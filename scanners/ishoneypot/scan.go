@@ -1,12 +1,18 @@
 package ishoneypot
 
 import (
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/decode"
+	"github.com/s-Amine/token-scan/transport"
 )
 
+// Name identifies the provider in drift statistics and diagnostics.
+const Name = "honeypot"
+
 // HoneypotResponse represents the structure of the response from Honeypot API.
 type HoneypotResponse struct {
 	Token          TokenInfo  `json:"token"`
@@ -16,17 +22,17 @@ type HoneypotResponse struct {
 		IsHoneypot bool `json:"isHoneypot"`
 	} `json:"honeypotResult"`
 	HolderAnalysis struct {
-		Holders         string    `json:"holders"`
-		Successful      string    `json:"successful"`
-		Failed          string    `json:"failed"`
-		Siphoned        string    `json:"siphoned"`
-		AverageTax      int       `json:"averageTax"`
-		AverageGas      float64   `json:"averageGas"`
-		HighestTax      int       `json:"highestTax"`
-		HighTaxWallets  string    `json:"highTaxWallets"`
-		TaxDistribution []TaxInfo `json:"taxDistribution"`
-		SnipersFailed   int       `json:"snipersFailed"`
-		SnipersSuccess  int       `json:"snipersSuccess"`
+		Holders         decode.Int   `json:"holders"`
+		Successful      decode.Int   `json:"successful"`
+		Failed          decode.Int   `json:"failed"`
+		Siphoned        decode.Int   `json:"siphoned"`
+		AverageTax      decode.Float `json:"averageTax"`
+		AverageGas      decode.Float `json:"averageGas"`
+		HighestTax      decode.Float `json:"highestTax"`
+		HighTaxWallets  decode.Int   `json:"highTaxWallets"`
+		TaxDistribution []TaxInfo    `json:"taxDistribution"`
+		SnipersFailed   decode.Int   `json:"snipersFailed"`
+		SnipersSuccess  decode.Int   `json:"snipersSuccess"`
 	} `json:"holderAnalysis"`
 	ContractCode struct {
 		OpenSource     bool `json:"openSource"`
@@ -38,65 +44,76 @@ type HoneypotResponse struct {
 	Router      string    `json:"router"`
 	Pair        PairInfo  `json:"pair"`
 	PairAddress string    `json:"pairAddress"`
+	// DecodeProblems lists the fields that could not be decoded and were left empty.
+	DecodeProblems []decode.Problem `json:"decodeProblems,omitempty" decode:"-"`
 }
 
 // TokenInfo represents token information.
 type TokenInfo struct {
-	Name         string `json:"name"`
-	Symbol       string `json:"symbol"`
-	Decimals     int    `json:"decimals"`
-	Address      string `json:"address"`
-	TotalHolders int    `json:"totalHolders"`
+	Name         string     `json:"name"`
+	Symbol       string     `json:"symbol"`
+	Decimals     decode.Int `json:"decimals"`
+	Address      string     `json:"address"`
+	TotalHolders decode.Int `json:"totalHolders"`
 }
 
 // Simulation represents simulation data.
 type Simulation struct {
-	BuyTax      int    `json:"buyTax"`
-	SellTax     int    `json:"sellTax"`
-	TransferTax int    `json:"transferTax"`
-	BuyGas      string `json:"buyGas"`
-	SellGas     string `json:"sellGas"`
+	BuyTax      decode.Float `json:"buyTax"`
+	SellTax     decode.Float `json:"sellTax"`
+	TransferTax decode.Float `json:"transferTax"`
+	BuyGas      decode.Int   `json:"buyGas"`
+	SellGas     decode.Int   `json:"sellGas"`
 }
 
 // TaxInfo represents tax information.
 type TaxInfo struct {
-	Tax   int `json:"tax"`
-	Count int `json:"count"`
+	Tax   decode.Float `json:"tax"`
+	Count decode.Int   `json:"count"`
 }
 
 // ChainInfo represents chain information.
 type ChainInfo struct {
-	ID        string `json:"id"`
-	Name      string `json:"name"`
-	ShortName string `json:"shortName"`
-	Currency  string `json:"currency"`
+	ID        decode.String `json:"id"`
+	Name      string        `json:"name"`
+	ShortName string        `json:"shortName"`
+	Currency  string        `json:"currency"`
 }
 
 // PairInfo represents pair information.
 type PairInfo struct {
-	PairName           string  `json:"name"`
-	PairAddress        string  `json:"address"`
-	Token0             string  `json:"token0"`
-	Token1             string  `json:"token1"`
-	Type               string  `json:"type"`
-	ChainId            string  `json:"chainId"`
-	Reserves0          string  `json:"reserves0"`
-	Reserves1          string  `json:"reserves1"`
-	Liquidity          float64 `json:"liquidity"`
-	Router             string  `json:"router"`
-	CreatedAtTimestamp string  `json:"createdAtTimestamp"`
-	CreationTxHash     string  `json:"creationTxHash"`
+	PairName           string        `json:"name"`
+	PairAddress        string        `json:"address"`
+	Token0             string        `json:"token0"`
+	Token1             string        `json:"token1"`
+	Type               string        `json:"type"`
+	ChainId            decode.String `json:"chainId"`
+	Reserves0          decode.BigInt `json:"reserves0"`
+	Reserves1          decode.BigInt `json:"reserves1"`
+	Liquidity          decode.Float  `json:"liquidity"`
+	Router             string        `json:"router"`
+	CreatedAtTimestamp decode.Int    `json:"createdAtTimestamp"`
+	CreationTxHash     string        `json:"creationTxHash"`
 }
 
 // Scan sends a request to Honeypot API to check if a token is a honeypot.
 // It returns the response received or an error if any.
 func Scan(tokenHash string) (HoneypotResponse, error) {
+	return ScanChain(chain.Default, tokenHash)
+}
+
+// ScanChain checks whether a token of the given chain is a honeypot.
+func ScanChain(c chain.Chain, tokenHash string) (HoneypotResponse, error) {
+	if !c.Honeypot {
+		return HoneypotResponse{}, fmt.Errorf("honeypot.is does not support chain %s", c.Name)
+	}
+
 	// Construct the URL
-	url := fmt.Sprintf("https://api.honeypot.is/v2/IsHoneypot?address=%v&chainID=1", tokenHash)
+	url := fmt.Sprintf("https://api.honeypot.is/v2/IsHoneypot?address=%v&chainID=%v", tokenHash, c.ID)
 	method := "GET"
 
 	// Create an HTTP client and request
-	client := &http.Client{}
+	client := transport.Client(0)
 	req, err := http.NewRequest(method, url, nil)
 	if err != nil {
 		return HoneypotResponse{}, err
@@ -115,12 +132,15 @@ func Scan(tokenHash string) (HoneypotResponse, error) {
 		return HoneypotResponse{}, err
 	}
 
-	// Unmarshal JSON response into HoneypotResponse struct
+	// Decode JSON response into HoneypotResponse struct, keeping track of
+	// fields that could not be decoded
 	var response HoneypotResponse
-	err = json.Unmarshal(body, &response)
+	report, err := decode.DecodeReport(body, &response)
 	if err != nil {
 		return HoneypotResponse{}, err
 	}
+	response.DecodeProblems = report.Problems
+	decode.RecordDrift(Name, report)
 
 	return response, nil
 }
@@ -1,56 +1,172 @@
 package multiscan
 
 import (
+	"time"
+
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/models"
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/decode"
 	"github.com/s-Amine/token-scan/scanners/goplus"
 	"github.com/s-Amine/token-scan/scanners/ishoneypot"
 	"github.com/s-Amine/token-scan/scanners/quickintel"
 	"github.com/s-Amine/token-scan/token"
 )
 
+// Options controls what ScanWithOptions returns.
+type Options struct {
+	// IncludeRaw attaches each provider's full decoded response to the result.
+	IncludeRaw bool
+	// Chain is the chain of the token; the zero value scans chain.Default.
+	Chain chain.Chain
+	// ScannedAt is the time of the scan, from which ages are measured; the
+	// zero value is the time the scan starts.
+	ScannedAt time.Time
+}
+
+// Result is the outcome of a multiscan.
+type Result struct {
+	Unified *token.TokenInfo `json:"unified"`
+	Raw     *RawResponses    `json:"raw,omitempty"`
+	// DecodeProblems lists, per provider, the response fields that could not
+	// be decoded and were left empty.
+	DecodeProblems map[string][]decode.Problem `json:"decode_problems,omitempty"`
+	// ScannedAt is the time of the scan.
+	ScannedAt time.Time `json:"-"`
+}
+
+// RawResponses holds the decoded responses of every provider. A provider whose
+// scan failed has a nil response and its error message in Errors.
+type RawResponses struct {
+	GoPlus     *models.ResponseWrapperTokenSecurityResultAnon `json:"goplus,omitempty"`
+	Honeypot   *ishoneypot.HoneypotResponse                   `json:"honeypot,omitempty"`
+	QuickIntel *quickintel.QuickIntelResponse                 `json:"quickintel,omitempty"`
+	Errors     map[string]string                              `json:"errors,omitempty"`
+}
+
+// Provider names used as keys of RawResponses.Errors.
+const (
+	ProviderGoPlus     = "goplus"
+	ProviderHoneypot   = "honeypot"
+	ProviderQuickIntel = "quickintel"
+)
+
 // MultiScan performs multiple scans using different scanners and unifies the results into one TokenInfo.
 func Scan(tokenHash string) *token.TokenInfo {
+	return ScanWithOptions(tokenHash, Options{}).Unified
+}
+
+// ScanWithOptions performs multiple scans using different scanners, unifies the
+// results and, when requested, keeps the raw provider responses alongside.
+func ScanWithOptions(tokenHash string, opts Options) *Result {
+	c := opts.Chain
+	if c.Name == "" {
+		c = chain.Default
+	}
+	scannedAt := opts.ScannedAt
+	if scannedAt.IsZero() {
+		scannedAt = time.Now()
+	}
+
 	// Channels to receive scan results from different scanners
-	goPlusScanResultChan := make(chan *token.TokenInfo)
-	isHoneypotScanResultChan := make(chan *token.TokenInfo)
-	quickIntelScanResultChan := make(chan *token.TokenInfo)
+	goPlusScanResultChan := make(chan models.ResponseWrapperTokenSecurityResultAnon)
+	isHoneypotScanResultChan := make(chan ishoneypot.HoneypotResponse)
+	quickIntelScanResultChan := make(chan quickintel.QuickIntelResponse)
+	errChan := make(chan providerError, 3)
 
 	// Perform GoPlus scan concurrently
 	go func() {
-		goPlusScanResult, _ := goplus.Scan(tokenHash)
-		goPlusTokenInfo := token.InitTokenInfoFromGoPlus(goPlusScanResult)
-		goPlusScanResultChan <- goPlusTokenInfo
+		goPlusScanResult, err := goplus.ScanChain(c, tokenHash)
+		if err != nil {
+			errChan <- providerError{ProviderGoPlus, err}
+		}
+		goPlusScanResultChan <- goPlusScanResult
 	}()
 
 	// Perform isHoneypot scan concurrently
 	go func() {
-		isHoneypotScanResult, _ := ishoneypot.Scan(tokenHash)
-		honeypotTokenInfo := token.InitTokenInfoFromHoneypotResponse(isHoneypotScanResult)
-		isHoneypotScanResultChan <- honeypotTokenInfo
+		isHoneypotScanResult, err := ishoneypot.ScanChain(c, tokenHash)
+		if err != nil {
+			errChan <- providerError{ProviderHoneypot, err}
+		}
+		isHoneypotScanResultChan <- isHoneypotScanResult
 	}()
 
 	// Perform QuickIntel scan concurrently
 	go func() {
-		quickIntelScanResult, _ := quickintel.Scan(tokenHash)
-		quickIntelTokenInfo := token.InitTokenInfoFromQuickIntelResponse(quickIntelScanResult)
-		quickIntelScanResultChan <- quickIntelTokenInfo
+		quickIntelScanResult, err := quickintel.ScanChain(c, tokenHash)
+		if err != nil {
+			errChan <- providerError{ProviderQuickIntel, err}
+		}
+		quickIntelScanResultChan <- quickIntelScanResult
 	}()
 
 	// Variables to store scan results
-	var goPlusTokenInfo *token.TokenInfo
-	var honeypotTokenInfo *token.TokenInfo
-	var quickIntelTokenInfo *token.TokenInfo
+	var goPlusScanResult models.ResponseWrapperTokenSecurityResultAnon
+	var isHoneypotScanResult ishoneypot.HoneypotResponse
+	var quickIntelScanResult quickintel.QuickIntelResponse
 
 	// Receive scan results from channels
 	for i := 0; i < 3; i++ {
 		select {
-		case goPlusTokenInfo = <-goPlusScanResultChan:
-		case honeypotTokenInfo = <-isHoneypotScanResultChan:
-		case quickIntelTokenInfo = <-quickIntelScanResultChan:
+		case goPlusScanResult = <-goPlusScanResultChan:
+		case isHoneypotScanResult = <-isHoneypotScanResultChan:
+		case quickIntelScanResult = <-quickIntelScanResultChan:
 		}
 	}
+	close(errChan)
 
 	// Unify scan results into one TokenInfo
-	unifiedInfo := token.UnifyTokenInfo(goPlusTokenInfo, honeypotTokenInfo, quickIntelTokenInfo)
+	result := &Result{
+		Unified: token.UnifyTokenInfo(
+			token.InitTokenInfoFromGoPlus(goPlusScanResult),
+			token.InitTokenInfoFromHoneypotResponse(isHoneypotScanResult, scannedAt),
+			token.InitTokenInfoFromQuickIntelResponse(quickIntelScanResult),
+		),
+		ScannedAt: scannedAt,
+	}
+	result.Unified.Risk = token.AssessRisk(result.Unified)
+	result.addDecodeProblems(ProviderHoneypot, isHoneypotScanResult.DecodeProblems)
+	result.addDecodeProblems(ProviderQuickIntel, quickIntelScanResult.DecodeProblems)
+
+	if opts.IncludeRaw {
+		raw := &RawResponses{
+			GoPlus:     &goPlusScanResult,
+			Honeypot:   &isHoneypotScanResult,
+			QuickIntel: &quickIntelScanResult,
+		}
+		for providerErr := range errChan {
+			if raw.Errors == nil {
+				raw.Errors = make(map[string]string)
+			}
+			raw.Errors[providerErr.provider] = providerErr.err.Error()
+			switch providerErr.provider {
+			case ProviderGoPlus:
+				raw.GoPlus = nil
+			case ProviderHoneypot:
+				raw.Honeypot = nil
+			case ProviderQuickIntel:
+				raw.QuickIntel = nil
+			}
+		}
+		result.Raw = raw
+	}
+
+	return result
+}
+
+// addDecodeProblems records the decode problems of a provider.
+func (r *Result) addDecodeProblems(provider string, problems []decode.Problem) {
+	if len(problems) == 0 {
+		return
+	}
+	if r.DecodeProblems == nil {
+		r.DecodeProblems = make(map[string][]decode.Problem)
+	}
+	r.DecodeProblems[provider] = problems
+}
 
-	return unifiedInfo
+// providerError associates a scan error with the provider that returned it.
+type providerError struct {
+	provider string
+	err      error
 }
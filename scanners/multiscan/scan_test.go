@@ -0,0 +1,130 @@
+package multiscan
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/s-Amine/token-scan/providertest"
+	"github.com/s-Amine/token-scan/scanners/quickintel"
+	"github.com/s-Amine/token-scan/token"
+	"github.com/s-Amine/token-scan/transport"
+)
+
+// Tokens scripted on the fake providers.
+const (
+	cleanToken    = "0x00000000000000000000000000000000000000a1"
+	honeypotToken = "0x00000000000000000000000000000000000000a2"
+	errorToken    = "0x00000000000000000000000000000000000000a3"
+	slowToken     = "0x00000000000000000000000000000000000000a4"
+)
+
+// slowDelay is how long the fake providers take to answer for slowToken.
+const slowDelay = 200 * time.Millisecond
+
+// fakeProviders points every scanner at a fake provider server scripted with
+// the test tokens.
+func fakeProviders(t *testing.T) *providertest.Server {
+	t.Helper()
+
+	server := providertest.NewServer()
+	server.SlowDelay = slowDelay
+	server.Script(honeypotToken, providertest.BehaviorHoneypot)
+	server.Script(errorToken, providertest.BehaviorError)
+	server.Script(slowToken, providertest.BehaviorSlow)
+	transport.SetRoundTripper(server.Transport())
+	t.Cleanup(func() {
+		transport.SetRoundTripper(nil)
+		server.Close()
+	})
+	return server
+}
+
+func TestScanWithOptions(t *testing.T) {
+	fakeProviders(t)
+
+	tests := []struct {
+		address  string
+		honeypot bool
+		level    string
+	}{
+		{address: cleanToken, level: token.VerdictSafe},
+		{address: honeypotToken, honeypot: true, level: token.VerdictDanger},
+		{address: slowToken, level: token.VerdictSafe},
+	}
+	for _, tt := range tests {
+		start := time.Now()
+		result := ScanWithOptions(tt.address, Options{IncludeRaw: true})
+		elapsed := time.Since(start)
+
+		if len(result.Raw.Errors) != 0 {
+			t.Errorf("%s: got provider errors %v", tt.address, result.Raw.Errors)
+			continue
+		}
+		if result.Unified.TokenName != providertest.TokenName || result.Unified.TokenSymbol != providertest.TokenSymbol {
+			t.Errorf("%s: got token %q (%q)", tt.address, result.Unified.TokenName, result.Unified.TokenSymbol)
+		}
+		if result.Unified.IsHoneypot != tt.honeypot {
+			t.Errorf("%s: got honeypot %v, want %v", tt.address, result.Unified.IsHoneypot, tt.honeypot)
+		}
+		if result.Unified.Risk.Level != tt.level {
+			t.Errorf("%s: got verdict %s (%+v), want %s", tt.address, result.Unified.Risk.Level, result.Unified.Risk.Factors, tt.level)
+		}
+		if result.Raw.GoPlus == nil || result.Raw.Honeypot == nil || result.Raw.QuickIntel == nil {
+			t.Errorf("%s: got raw responses %+v, want all three", tt.address, result.Raw)
+		}
+		// The providers are queried concurrently
+		if tt.address == slowToken && (elapsed < slowDelay || elapsed >= 3*slowDelay) {
+			t.Errorf("%s: scan took %v, want about %v", tt.address, elapsed, slowDelay)
+		}
+	}
+}
+
+func TestScanWithOptionsProviderErrors(t *testing.T) {
+	fakeProviders(t)
+
+	result := ScanWithOptions(errorToken, Options{IncludeRaw: true})
+	for _, provider := range []string{ProviderGoPlus, ProviderHoneypot, ProviderQuickIntel} {
+		if _, failed := result.Raw.Errors[provider]; !failed {
+			t.Errorf("got errors %v, want an error from %s", result.Raw.Errors, provider)
+		}
+	}
+	if result.Raw.GoPlus != nil || result.Raw.Honeypot != nil || result.Raw.QuickIntel != nil {
+		t.Errorf("got raw responses %+v, want none from failed providers", result.Raw)
+	}
+	if result.Unified.IsHoneypot {
+		t.Errorf("got honeypot flag without any provider answer")
+	}
+	if risk := result.Unified.Risk; risk.Level != token.VerdictUnknown || !risk.Incomplete || len(risk.Factors) != 0 {
+		t.Errorf("got verdict %+v, want an incomplete unknown verdict without factors", risk)
+	}
+}
+
+func TestScanWithOptionsQuickIntelRequest(t *testing.T) {
+	server := fakeProviders(t)
+
+	ScanWithOptions(cleanToken, Options{})
+
+	var found bool
+	for _, req := range server.Requests() {
+		if req.URL.Path != providertest.QuickIntelPath {
+			continue
+		}
+		found = true
+		if req.Method != http.MethodPost {
+			t.Errorf("got QuickIntel method %s, want POST", req.Method)
+		}
+		var body quickintel.AuditRequest
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding QuickIntel payload: %v", err)
+		}
+		want := quickintel.AuditRequest{Chain: "eth", TokenAddress: cleanToken, Tier: quickintel.TierBasic}
+		if body != want {
+			t.Errorf("got QuickIntel payload %+v, want %+v", body, want)
+		}
+	}
+	if !found {
+		t.Error("QuickIntel was not queried")
+	}
+}
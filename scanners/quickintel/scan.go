@@ -1,31 +1,156 @@
 package quickintel
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"reflect"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/decode"
+	"github.com/s-Amine/token-scan/transport"
+)
+
+// Name identifies the provider in drift statistics and diagnostics.
+const Name = "quickintel"
+
+// Audit tiers accepted by the QuickIntel API.
+const (
+	TierBasic   = "basic"
+	TierPremium = "premium"
+)
+
+// DefaultURL is the QuickIntel full audit endpoint.
+const DefaultURL = "https://app.quickintel.io/api/quicki/getquickiauditfull"
+
+// apiKeyHeader is the header carrying the QuickIntel API key.
+const apiKeyHeader = "X-QKNTL-KEY"
+
+// Config holds the QuickIntel credentials and request settings.
+type Config struct {
+	APIKey string `json:"api_key,omitempty"`
+	// Tier is the audit tier to request; it defaults to TierBasic.
+	Tier string `json:"tier,omitempty"`
+	// URL overrides DefaultURL.
+	URL string `json:"url,omitempty"`
+	// Timeout is the request timeout in seconds.
+	Timeout int `json:"timeout,omitempty"`
+}
+
+var (
+	configMu sync.RWMutex
+	config   Config
 )
 
+// Configure sets the configuration used by subsequent scans.
+func Configure(cfg Config) {
+	configMu.Lock()
+	defer configMu.Unlock()
+
+	config = cfg
+}
+
+// currentConfig returns the configured settings with defaults filled in.
+func currentConfig() Config {
+	configMu.RLock()
+	cfg := config
+	configMu.RUnlock()
+
+	if cfg.Tier == "" {
+		cfg.Tier = TierBasic
+	}
+	if cfg.URL == "" {
+		cfg.URL = DefaultURL
+	}
+
+	return cfg
+}
+
+// ConfiguredTier returns the audit tier scans request.
+func ConfiguredTier() string {
+	return currentConfig().Tier
+}
+
+// TierFields returns the paths of the response fields only returned by tier,
+// in the dotted form decode reports drift with.
+func TierFields(tier string) []string {
+	return tierFields(reflect.TypeOf(QuickIntelResponse{}), "", tier)
+}
+
+// tierFields collects the fields of struct type t tagged with tier.
+func tierFields(t reflect.Type, path, tier string) []string {
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		if path != "" {
+			name = path + "." + name
+		}
+		if f.Tag.Get("tier") == tier {
+			fields = append(fields, name)
+		}
+		// Nested response objects are declared as anonymous structs
+		if f.Type.Kind() == reflect.Struct && f.Type.Name() == "" {
+			fields = append(fields, tierFields(f.Type, name, tier)...)
+		}
+	}
+	return fields
+}
+
+// AuditRequest represents the request body of the full audit endpoint.
+type AuditRequest struct {
+	Chain        string `json:"chain"`
+	TokenAddress string `json:"tokenAddress"`
+	Tier         string `json:"tier"`
+}
+
 // QuickIntelResponse represents the structure of the response from QuickIntel API.
 type QuickIntelResponse struct {
 	TokenDetails struct {
-		TokenName        string `json:"tokenName"`
-		TokenSymbol      string `json:"tokenSymbol"`
-		TokenDecimals    int    `json:"tokenDecimals"`
-		TokenLogo        string `json:"tokenLogo"`
-		TokenOwner       string `json:"tokenOwner"`
-		TokenSupply      int    `json:"tokenSupply"`
-		TokenCreatedDate int64  `json:"tokenCreatedDate"`
+		TokenName        string        `json:"tokenName"`
+		TokenSymbol      string        `json:"tokenSymbol"`
+		TokenDecimals    decode.Int    `json:"tokenDecimals"`
+		TokenLogo        string        `json:"tokenLogo"`
+		TokenOwner       string        `json:"tokenOwner"`
+		TokenSupply      decode.BigInt `json:"tokenSupply"`
+		TokenCreatedDate decode.Int    `json:"tokenCreatedDate"`
 		QuickiTokenHash  struct {
 			ExactQHash   string `json:"exact_qHash"`
 			SimilarQHash string `json:"similar_qHash"`
 		} `json:"quickiTokenHash"`
 	} `json:"tokenDetails"`
 	TokenDynamicDetails struct {
-		LastUpdatedTimestamp int64 `json:"lastUpdatedTimestamp"`
-		IsHoneypot           bool  `json:"is_Honeypot"`
+		LastUpdatedTimestamp decode.Int `json:"lastUpdatedTimestamp"`
+		IsHoneypot           bool       `json:"is_Honeypot"`
+		// The fields tagged tier:"premium" are only returned by the premium tier;
+		// numbers are pointers so that the nulls of the basic tier stay nil.
+		HoneypotReason      string        `json:"honeypot_Reason" tier:"premium"`
+		BuyTax              *decode.Float `json:"buy_Tax" tier:"premium"`
+		SellTax             *decode.Float `json:"sell_Tax" tier:"premium"`
+		TransferTax         *decode.Float `json:"transfer_Tax" tier:"premium"`
+		PostReenableBuyTax  *decode.Float `json:"post_Reenable_Buy_Tax" tier:"premium"`
+		PostReenableSellTax *decode.Float `json:"post_Reenable_Sell_Tax" tier:"premium"`
+		MaxTransaction      decode.BigInt `json:"max_Transaction" tier:"premium"`
+		MaxTransactionPct   *decode.Float `json:"max_Transaction_Percent" tier:"premium"`
+		MaxWallet           decode.BigInt `json:"max_Wallet" tier:"premium"`
+		MaxWalletPct        *decode.Float `json:"max_Wallet_Percent" tier:"premium"`
+		TokenSupplyBurned   decode.BigInt `json:"token_Supply_Burned" tier:"premium"`
+		LpPair              string        `json:"lp_Pair" tier:"premium"`
+		LpSupply            decode.BigInt `json:"lp_Supply" tier:"premium"`
+		LpBurnedPercent     *decode.Float `json:"lp_Burned_Percent" tier:"premium"`
+		LpLockedPercent     *decode.Float `json:"lp_Locked_Percent" tier:"premium"`
+		LpLockedUntil       *decode.Int   `json:"lp_Locked_Until" tier:"premium"`
+		LpHolderCount       *decode.Int   `json:"lp_Holder_Count" tier:"premium"`
+		TokenHolderCount    *decode.Int   `json:"token_Holder_Count" tier:"premium"`
+		PriceImpactPercent  *decode.Float `json:"price_Impact" tier:"premium"`
 	} `json:"tokenDynamicDetails"`
 	QuickiAudit struct {
 		ContractCreator           string   `json:"contract_Creator"`
@@ -45,7 +170,6 @@ type QuickIntelResponse struct {
 		HasObfuscatedAddressRisk  bool     `json:"has_Obfuscated_Address_Risk"`
 		ObfuscatedAddressList     string   `json:"obfuscated_Address_List"`
 		CanMint                   bool     `json:"can_Mint"`
-		CantMintRenounced         string   `json:"cant_Mint_Renounced"`
 		CanBurn                   bool     `json:"can_Burn"`
 		CanBlacklist              bool     `json:"can_Blacklist"`
 		CantBlacklistRenounced    bool     `json:"cant_Blacklist_Renounced"`
@@ -82,31 +206,64 @@ type QuickIntelResponse struct {
 		HasGeneralVulnerabilities bool     `json:"has_General_Vulnerabilities"`
 		GeneralVulnerabilities    string   `json:"general_Vulnerabilities"`
 	} `json:"quickiAudit"`
-	ProjectVerified  bool        `json:"projectVerified"`
-	KycVerifications interface{} `json:"kycVerifications"`
-	ExternalAudits   interface{} `json:"externalAudits"`
+	ProjectVerified  bool              `json:"projectVerified"`
+	KycVerifications []KycVerification `json:"kycVerifications"`
+	ExternalAudits   []ExternalAudit   `json:"externalAudits"`
+	// DecodeProblems lists the fields that could not be decoded and were left empty.
+	DecodeProblems []decode.Problem `json:"decodeProblems,omitempty" decode:"-"`
+}
+
+// KycVerification represents a KYC check of the project team.
+type KycVerification struct {
+	Provider string `json:"kycProvider"`
+	URL      string `json:"kycUrl"`
+	Date     string `json:"kycDate"`
+	Level    string `json:"kycLevel"`
+}
+
+// ExternalAudit represents a third-party audit of the contract.
+type ExternalAudit struct {
+	Provider string `json:"auditProvider"`
+	URL      string `json:"auditUrl"`
+	Date     string `json:"auditDate"`
+	Status   string `json:"auditStatus"`
 }
 
 // Scan sends a request to QuickIntel API to get information about a token
 // identified by its hash. It returns the response received or an error if any.
 func Scan(tokenHash string) (QuickIntelResponse, error) {
+	return ScanChain(chain.Default, tokenHash)
+}
+
+// ScanChain sends a request to QuickIntel API about a token of the given chain.
+func ScanChain(c chain.Chain, tokenHash string) (QuickIntelResponse, error) {
 	var response QuickIntelResponse
 
-	// URL and request method
-	url := "https://app.quickintel.io/api/quicki/getquickiauditfull"
+	// Resolve settings and request method
+	cfg := currentConfig()
 	method := "POST"
 
 	// Prepare the request body
-	request := fmt.Sprintf("{\"chain\":\"eth\",\"tokenAddress\":\"%v\",\"tier\":\"basic\"}", tokenHash)
-	payload := strings.NewReader(request)
+	request, err := json.Marshal(AuditRequest{
+		Chain:        c.QuickIntel,
+		TokenAddress: tokenHash,
+		Tier:         cfg.Tier,
+	})
+	if err != nil {
+		return response, err
+	}
+	payload := bytes.NewReader(request)
 
 	// Create HTTP client and request
-	client := &http.Client{}
-	req, err := http.NewRequest(method, url, payload)
+	client := transport.Client(time.Duration(cfg.Timeout) * time.Second)
+	req, err := http.NewRequest(method, cfg.URL, payload)
 	if err != nil {
 		return response, err
 	}
 	req.Header.Add("Content-Type", "application/json")
+	if cfg.APIKey != "" {
+		req.Header.Add(apiKeyHeader, cfg.APIKey)
+	}
 
 	// Send the request
 	res, err := client.Do(req)
@@ -120,12 +277,18 @@ func Scan(tokenHash string) (QuickIntelResponse, error) {
 	if err != nil {
 		return response, err
 	}
+	if res.StatusCode != http.StatusOK {
+		return response, fmt.Errorf("quickintel returned status %d: %s", res.StatusCode, bytes.TrimSpace(body))
+	}
 
-	// Unmarshal JSON response into QuickIntelResponse struct
-	err = json.Unmarshal(body, &response)
+	// Decode JSON response into QuickIntelResponse struct, keeping track of
+	// fields that could not be decoded
+	report, err := decode.DecodeReport(body, &response)
 	if err != nil {
 		return response, err
 	}
+	response.DecodeProblems = report.Problems
+	decode.RecordDrift(Name, report)
 
 	return response, nil
 }
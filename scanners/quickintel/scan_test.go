@@ -0,0 +1,47 @@
+package quickintel
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/s-Amine/token-scan/decode"
+)
+
+func TestTierFields(t *testing.T) {
+	// A basic tier audit is a full audit without the premium fields
+	data, err := json.Marshal(QuickIntelResponse{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		t.Fatal(err)
+	}
+	dynamic := body["tokenDynamicDetails"].(map[string]interface{})
+	premium := TierFields(TierPremium)
+	for _, field := range premium {
+		key, ok := strings.CutPrefix(field, "tokenDynamicDetails.")
+		if !ok {
+			t.Fatalf("premium field %s is outside tokenDynamicDetails", field)
+		}
+		delete(dynamic, key)
+	}
+	sort.Strings(premium)
+	if data, err = json.Marshal(body); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := decode.DecodeReport(data, &QuickIntelResponse{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(report.MissingFields) != fmt.Sprint(premium) {
+		t.Errorf("got missing fields %v, want the premium fields %v", report.MissingFields, premium)
+	}
+	if len(TierFields(TierBasic)) != 0 {
+		t.Errorf("got basic tier fields %v, want none", TierFields(TierBasic))
+	}
+}
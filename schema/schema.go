@@ -0,0 +1,195 @@
+// Package schema generates the JSON Schema of the token-scan output from the
+// Go types it is marshaled from.
+package schema
+
+//go:generate sh -c "cd .. && go run . schema > schema/envelope.schema.json"
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/models"
+	"github.com/s-Amine/token-scan/decode"
+	"github.com/s-Amine/token-scan/envelope"
+	"github.com/s-Amine/token-scan/scanners/ishoneypot"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/scanners/quickintel"
+	"github.com/s-Amine/token-scan/token"
+)
+
+// ID identifies the published schema.
+const ID = "https://github.com/s-Amine/token-scan/schema/envelope.schema.json"
+
+// Schema is a JSON Schema document.
+type Schema map[string]interface{}
+
+// knownTypes are types whose JSON encoding differs from their Go structure.
+var knownTypes = map[reflect.Type]Schema{
+	reflect.TypeOf(time.Time{}):                {"type": "string", "format": "date-time"},
+	reflect.TypeOf(big.Int{}):                  {"type": "integer"},
+	reflect.TypeOf(decode.BigInt{}):            {"type": []string{"string", "null"}, "pattern": "^-?[0-9]+$"},
+	reflect.TypeOf(json.RawMessage{}):          {},
+	reflect.TypeOf((*interface{})(nil)).Elem(): {},
+}
+
+// resultTypes are the result types of each envelope kind.
+var resultTypes = []struct {
+	kind   string
+	result interface{}
+}{
+	{envelope.KindMultiscan, token.TokenInfo{}},
+	{envelope.KindMultiscan, multiscan.Result{}},
+	{envelope.KindGoPlus, models.ResponseWrapperTokenSecurityResultAnon{}},
+	{envelope.KindHoneypot, ishoneypot.HoneypotResponse{}},
+	{envelope.KindQuickIntel, quickintel.QuickIntelResponse{}},
+}
+
+// Envelope returns the JSON Schema of the output envelope. The result is
+// described by one schema per kind; a multiscan result is either the unified
+// token information or, with raw responses, a multiscan result.
+func Envelope() Schema {
+	g := &generator{defs: Schema{}}
+
+	root := g.object(reflect.TypeOf(envelope.Envelope{}))
+	properties := root["properties"].(Schema)
+	properties["schema_version"] = Schema{"const": envelope.SchemaVersion}
+
+	var kinds []interface{}
+	var conditions []interface{}
+	byKind := map[string][]interface{}{}
+	for _, rt := range resultTypes {
+		if _, ok := byKind[rt.kind]; !ok {
+			kinds = append(kinds, rt.kind)
+		}
+		byKind[rt.kind] = append(byKind[rt.kind], g.schema(reflect.TypeOf(rt.result)))
+	}
+	properties["kind"] = Schema{"enum": kinds}
+	for _, kind := range kinds {
+		result := Schema{"anyOf": byKind[kind.(string)]}
+		if len(byKind[kind.(string)]) == 1 {
+			result = byKind[kind.(string)][0].(Schema)
+		}
+		conditions = append(conditions, Schema{
+			"if":   Schema{"properties": Schema{"kind": Schema{"const": kind}}},
+			"then": Schema{"properties": Schema{"result": result}},
+		})
+	}
+
+	root["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	root["$id"] = ID
+	root["title"] = "token-scan output"
+	root["allOf"] = conditions
+	root["$defs"] = g.defs
+	return root
+}
+
+// generator builds schemas, collecting named struct types in defs.
+type generator struct {
+	defs Schema
+}
+
+// schema returns the schema of t, referencing named structs through $defs.
+func (g *generator) schema(t reflect.Type) Schema {
+	if known, ok := knownTypes[t]; ok {
+		return known
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		// Nil pointers marshal as null.
+		return Schema{"anyOf": []interface{}{g.schema(t.Elem()), Schema{"type": "null"}}}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "contentEncoding": "base64"}
+		}
+		return Schema{"type": []interface{}{"array", "null"}, "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": []interface{}{"object", "null"}, "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name := defName(t)
+		if _, ok := g.defs[name]; !ok {
+			// Reserve the name first so that recursive types terminate.
+			g.defs[name] = Schema{}
+			g.defs[name] = g.object(t)
+		}
+		return Schema{"$ref": "#/$defs/" + name}
+	}
+	return Schema{}
+}
+
+// object returns the schema of a struct from its JSON field names. Fields
+// without omitempty are required.
+func (g *generator) object(t reflect.Type) Schema {
+	properties := Schema{}
+	var required []interface{}
+	g.addFields(t, properties, &required)
+
+	object := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		object["required"] = required
+	}
+	return object
+}
+
+// addFields adds the JSON fields of struct t, including those of embedded
+// structs, to properties.
+func (g *generator) addFields(t reflect.Type, properties Schema, required *[]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(embedded, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := g.schema(field.Type)
+		if strings.Contains(","+options+",", ",string,") {
+			fieldSchema = Schema{"type": "string"}
+		}
+		properties[name] = fieldSchema
+		if !strings.Contains(","+options+",", ",omitempty,") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// defName names the definition of a named type after its package and name.
+func defName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	return pkg + "." + t.Name()
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/s-Amine/token-scan/schema"
+)
+
+// schemaCommand implements the schema command. It prints the JSON Schema of
+// the output envelope.
+func schemaCommand(flags *flag.FlagSet) func(args []string) {
+	return func(args []string) {
+		jsonData, err := json.MarshalIndent(schema.Envelope(), "", "  ")
+		if err != nil {
+			fmt.Printf("Error marshalling JSON: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(jsonData))
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/server"
+	"github.com/s-Amine/token-scan/watch"
+)
+
+// Timeouts of the serve command.
+const (
+	// shutdownTimeout is how long serve waits for requests in progress to
+	// complete when stopping.
+	shutdownTimeout = 30 * time.Second
+	// readHeaderTimeout and readTimeout bound how long a client may take to
+	// send the headers and the whole request. Scans can take longer, so
+	// writes are not bounded.
+	readHeaderTimeout = 10 * time.Second
+	readTimeout       = 30 * time.Second
+)
+
+// serveCommand implements the serve command. It answers scan requests over
+// HTTP until SIGINT or SIGTERM and sends an alert for every scanned token
+// with a risk factor of at least -min-severity.
+func serveCommand(flags *flag.FlagSet) func(args []string) {
+	addr := flags.String("addr", "127.0.0.1:8080", "Address to listen on")
+	minSeverity := flags.String("min-severity", string(watch.DefaultMinSeverity), "Lowest risk factor severity to alert on: low, medium, high or critical")
+	noAlerts := flags.Bool("no-alerts", false, "Do not send alerts")
+	noHistory := flags.Bool("no-history", false, "Do not record the scans in the history store")
+	maxScans := flags.Int("max-scans", server.DefaultMaxScans, "Most scans run at once; further scan requests get status 503")
+	useFixtures := addFixtureFlags(flags)
+
+	return func(args []string) {
+		if len(args) != 0 {
+			usageError(flags, "unexpected arguments %q", args)
+		}
+		severity := parseSeverity(flags, *minSeverity)
+		if *maxScans <= 0 {
+			usageError(flags, "invalid -max-scans %d", *maxScans)
+		}
+
+		cfg := loadConfig()
+		useFixtures()
+		router := alertRouter(cfg.Alerts, *noAlerts)
+		recording := !*noHistory && !cfg.History.Disabled
+
+		s := &server.Server{
+			Chain:    globals.scanChain,
+			MaxScans: *maxScans,
+			OnScan: func(c chain.Chain, address string, result *multiscan.Result) {
+				verbosef("Scanned %s on %s", address, c)
+				reportProviderErrors(address, result)
+				if recording {
+					recordHistory(cfg.History.Path, "multiscan", c, address, result)
+				}
+				sendRiskAlert(router, severity, c, address, result)
+			},
+		}
+		httpServer := &http.Server{
+			Addr:              *addr,
+			Handler:           s.Handler(),
+			ReadHeaderTimeout: readHeaderTimeout,
+			ReadTimeout:       readTimeout,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		stopped := make(chan struct{})
+		go func() {
+			defer close(stopped)
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+			defer cancel()
+			httpServer.Shutdown(shutdownCtx)
+		}()
+
+		infof("Listening on %s", *addr)
+		if err := httpServer.ListenAndServe(); err != http.ErrServerClosed {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		// Requests in progress complete before Shutdown returns
+		<-stopped
+		infof("Server stopped")
+	}
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/envelope"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/schema"
+	"github.com/s-Amine/token-scan/version"
+)
+
+// DefaultMaxScans is the default number of scans a server runs at once.
+const DefaultMaxScans = 8
+
+// addressPattern matches the token addresses accepted by the scan endpoint.
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// Server answers scan requests over HTTP with output envelopes:
+//
+//	GET /v1/scan/{address}?chain=<name or ID>&raw=true
+//	GET /v1/schema
+//	GET /healthz
+//
+// Scan requests beyond MaxScans in progress are answered with status 503.
+type Server struct {
+	// Chain is the chain of scan requests that do not name one; the zero
+	// value is chain.Default.
+	Chain chain.Chain
+
+	// Scan scans a token; it defaults to a multiscan with raw responses.
+	Scan func(c chain.Chain, address string) *multiscan.Result
+	// OnScan, when set, is called with every scan result.
+	OnScan func(c chain.Chain, address string, result *multiscan.Result)
+
+	// MaxScans bounds the scans run at once; zero uses DefaultMaxScans.
+	MaxScans int
+}
+
+// errorResponse is the body of failed requests.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler returns the HTTP handler of the server.
+func (s *Server) Handler() http.Handler {
+	maxScans := s.MaxScans
+	if maxScans <= 0 {
+		maxScans = DefaultMaxScans
+	}
+	slots := make(chan struct{}, maxScans)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/scan/{address}", func(w http.ResponseWriter, r *http.Request) {
+		s.handleScan(w, r, slots)
+	})
+	mux.HandleFunc("GET /v1/schema", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, schema.Envelope())
+	})
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "version": version.String()})
+	})
+	return mux
+}
+
+// handleScan scans the token of the request path, holding one of slots
+// during the scan. It fails fast when every slot is taken.
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request, slots chan struct{}) {
+	address := r.PathValue("address")
+	if !addressPattern.MatchString(address) {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"invalid token address " + strconv.Quote(address)})
+		return
+	}
+
+	c := s.Chain
+	if c.Name == "" {
+		c = chain.Default
+	}
+	if name := r.URL.Query().Get("chain"); name != "" {
+		var err error
+		if c, err = chain.Parse(name); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{err.Error()})
+			return
+		}
+	}
+	includeRaw := false
+	if raw := r.URL.Query().Get("raw"); raw != "" {
+		var err error
+		if includeRaw, err = strconv.ParseBool(raw); err != nil {
+			writeJSON(w, http.StatusBadRequest, errorResponse{"invalid raw parameter " + strconv.Quote(raw)})
+			return
+		}
+	}
+
+	select {
+	case slots <- struct{}{}:
+		defer func() { <-slots }()
+	default:
+		writeJSON(w, http.StatusServiceUnavailable, errorResponse{"too many scans in progress, try again later"})
+		return
+	}
+
+	result := s.scan(c, address)
+	if s.OnScan != nil {
+		s.OnScan(c, address, result)
+	}
+	writeJSON(w, http.StatusOK, envelope.Multiscan(c, address, result, includeRaw))
+}
+
+// scan scans a token with Scan or a multiscan.
+func (s *Server) scan(c chain.Chain, address string) *multiscan.Result {
+	if s.Scan != nil {
+		return s.Scan(c, address)
+	}
+	return multiscan.ScanWithOptions(address, multiscan.Options{Chain: c, IncludeRaw: true})
+}
+
+// writeJSON writes v as the JSON body of a response with the given status.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
@@ -0,0 +1,201 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/providertest"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/token"
+	"github.com/s-Amine/token-scan/transport"
+)
+
+// Tokens scripted on the fake providers.
+const (
+	cleanToken    = "0x00000000000000000000000000000000000000a1"
+	honeypotToken = "0x00000000000000000000000000000000000000a2"
+	errorToken    = "0x00000000000000000000000000000000000000a3"
+)
+
+// fakeProviders points every scanner at a fake provider server scripted with
+// the test tokens.
+func fakeProviders(t *testing.T) {
+	t.Helper()
+
+	server := providertest.NewServer()
+	server.Script(honeypotToken, providertest.BehaviorHoneypot)
+	server.Script(errorToken, providertest.BehaviorError)
+	transport.SetRoundTripper(server.Transport())
+	t.Cleanup(func() {
+		transport.SetRoundTripper(nil)
+		server.Close()
+	})
+}
+
+// scanResponse is the part of a scan envelope checked by the tests.
+type scanResponse struct {
+	Kind    string `json:"kind"`
+	Address string `json:"address"`
+	Chain   struct {
+		Name string `json:"name"`
+	} `json:"chain"`
+	// Result is the unified token information, or the whole multiscan with
+	// raw=true.
+	Result json.RawMessage   `json:"result"`
+	Errors map[string]string `json:"errors"`
+	Error  string            `json:"error"`
+}
+
+// get serves a GET request for target and decodes the JSON response.
+func get(t *testing.T, handler http.Handler, target string) (int, scanResponse) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+	var response scanResponse
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+		t.Fatalf("%s: decoding response %q: %v", target, recorder.Body.String(), err)
+	}
+	return recorder.Code, response
+}
+
+func TestScan(t *testing.T) {
+	fakeProviders(t)
+	var mu sync.Mutex
+	scanned := map[string]bool{}
+	handler := (&Server{OnScan: func(c chain.Chain, address string, result *multiscan.Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		scanned[address] = true
+	}}).Handler()
+
+	tests := []struct {
+		address  string
+		level    string
+		honeypot bool
+		failed   bool
+	}{
+		{address: cleanToken, level: token.VerdictSafe},
+		{address: honeypotToken, level: token.VerdictDanger, honeypot: true},
+		{address: errorToken, level: token.VerdictUnknown, failed: true},
+	}
+	for _, tt := range tests {
+		code, response := get(t, handler, "/v1/scan/"+tt.address)
+		if code != http.StatusOK {
+			t.Errorf("%s: got status %d (%s), want 200", tt.address, code, response.Error)
+			continue
+		}
+		if response.Kind != "multiscan" || response.Address != tt.address || response.Chain.Name != chain.Default.Name {
+			t.Errorf("%s: got kind %q, address %q and chain %q", tt.address, response.Kind, response.Address, response.Chain.Name)
+		}
+		info := &token.TokenInfo{}
+		if err := json.Unmarshal(response.Result, info); err != nil || info.Risk == nil {
+			t.Errorf("%s: got no risk verdict", tt.address)
+			continue
+		}
+		if info.Risk.Level != tt.level || info.IsHoneypot != tt.honeypot {
+			t.Errorf("%s: got verdict %s and honeypot %v, want %s and %v", tt.address, info.Risk.Level, info.IsHoneypot, tt.level, tt.honeypot)
+		}
+		if failed := len(response.Errors) > 0; failed != tt.failed {
+			t.Errorf("%s: got provider errors %v, want errors %v", tt.address, response.Errors, tt.failed)
+		}
+		if !scanned[tt.address] {
+			t.Errorf("%s: OnScan was not called", tt.address)
+		}
+	}
+
+	_, response := get(t, handler, "/v1/scan/"+cleanToken+"?raw=true")
+	var result multiscan.Result
+	if err := json.Unmarshal(response.Result, &result); err != nil || result.Unified == nil || result.Raw == nil || result.Raw.GoPlus == nil {
+		t.Errorf("got result %s with raw=true, want the unified information and raw responses", response.Result)
+	}
+}
+
+func TestScanChain(t *testing.T) {
+	var got chain.Chain
+	handler := (&Server{Chain: chain.Base, Scan: func(c chain.Chain, address string) *multiscan.Result {
+		got = c
+		return &multiscan.Result{Unified: &token.TokenInfo{}}
+	}}).Handler()
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", chain.Base.Name},
+		{"?chain=bsc", chain.BSC.Name},
+		{"?chain=56", chain.BSC.Name},
+	}
+	for _, tt := range tests {
+		code, response := get(t, handler, "/v1/scan/"+cleanToken+tt.query)
+		if code != http.StatusOK || got.Name != tt.want || response.Chain.Name != tt.want {
+			t.Errorf("%q: got status %d, scanned chain %q and envelope chain %q, want 200 and %q", tt.query, code, got.Name, response.Chain.Name, tt.want)
+		}
+	}
+}
+
+func TestScanBadRequests(t *testing.T) {
+	handler := (&Server{Scan: func(c chain.Chain, address string) *multiscan.Result {
+		t.Errorf("scanned %s for a bad request", address)
+		return &multiscan.Result{Unified: &token.TokenInfo{}}
+	}}).Handler()
+
+	for _, target := range []string{
+		"/v1/scan/0x1234",
+		"/v1/scan/" + cleanToken + "zz",
+		"/v1/scan/" + cleanToken + "?chain=nochain",
+		"/v1/scan/" + cleanToken + "?raw=maybe",
+	} {
+		code, response := get(t, handler, target)
+		if code != http.StatusBadRequest || response.Error == "" {
+			t.Errorf("%s: got status %d and error %q, want 400 with an error", target, code, response.Error)
+		}
+	}
+}
+
+func TestScanLimit(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := (&Server{MaxScans: 1, Scan: func(c chain.Chain, address string) *multiscan.Result {
+		if address == cleanToken {
+			started <- struct{}{}
+			<-release
+		}
+		return &multiscan.Result{Unified: &token.TokenInfo{}}
+	}}).Handler()
+
+	done := make(chan int)
+	go func() {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/scan/"+cleanToken, nil))
+		done <- recorder.Code
+	}()
+	<-started
+
+	// The only slot is taken, so further scans fail fast
+	if code, response := get(t, handler, "/v1/scan/"+honeypotToken); code != http.StatusServiceUnavailable || response.Error == "" {
+		t.Errorf("got status %d and error %q while the scan limit was reached, want 503 with an error", code, response.Error)
+	}
+
+	close(release)
+	if code := <-done; code != http.StatusOK {
+		t.Errorf("got status %d for the scan in progress, want 200", code)
+	}
+	if code, _ := get(t, handler, "/v1/scan/"+honeypotToken); code != http.StatusOK {
+		t.Errorf("got status %d once the slot was released, want 200", code)
+	}
+}
+
+func TestHealthAndSchema(t *testing.T) {
+	handler := (&Server{}).Handler()
+	for _, target := range []string{"/healthz", "/v1/schema"} {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		if recorder.Code != http.StatusOK || !json.Valid(recorder.Body.Bytes()) {
+			t.Errorf("%s: got status %d and body %q, want 200 with JSON", target, recorder.Code, recorder.Body.String())
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+)
+
+// DefaultBaseURL is the Telegram Bot API endpoint.
+const DefaultBaseURL = "https://api.telegram.org"
+
+// Defaults of the bot settings.
+const (
+	defaultPollTimeout       = 30 * time.Second
+	defaultWorkers           = 4
+	defaultMessagesPerMinute = 10
+	retryDelay               = 5 * time.Second
+	// requestMargin is how much longer than the poll timeout a request may take.
+	requestMargin = 15 * time.Second
+)
+
+// Config represents the telegram section of the configuration file.
+type Config struct {
+	Token string `json:"token,omitempty"`
+	// BaseURL replaces DefaultBaseURL, e.g. to run against a local stand-in.
+	BaseURL string `json:"base_url,omitempty"`
+	// AllowedChats restricts the chats the bot answers. Either it or
+	// AllowAllChats is required.
+	AllowedChats []int64 `json:"allowed_chats,omitempty"`
+	// AllowAllChats answers every chat, for public bots.
+	AllowAllChats bool `json:"allow_all_chats,omitempty"`
+	// Workers bounds the messages answered at once.
+	Workers int `json:"workers,omitempty"`
+	// MessagesPerMinute bounds the messages handled per chat and minute; the
+	// others are dropped.
+	MessagesPerMinute int `json:"messages_per_minute,omitempty"`
+}
+
+// Bot answers messages containing token addresses with a risk summary.
+type Bot struct {
+	Config
+	// Chain is the chain of messages that do not name one; the zero value is
+	// chain.Default.
+	Chain  chain.Chain
+	Client *http.Client
+	// PollTimeout is how long a getUpdates request waits for messages.
+	PollTimeout time.Duration
+
+	// Scan scans a token; it defaults to a multiscan of the token on c.
+	Scan func(c chain.Chain, address string) *multiscan.Result
+	// OnScan, when set, is called with every scan result.
+	OnScan func(c chain.Chain, address string, result *multiscan.Result)
+	// OnError, when set, is called with errors of the Bot API.
+	OnError func(err error)
+
+	offset int64
+	// received holds the recent message times per chat, for MessagesPerMinute.
+	received map[int64][]time.Time
+}
+
+// Update is an update returned by getUpdates.
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message,omitempty"`
+}
+
+// Message is a chat message.
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text,omitempty"`
+}
+
+// Chat identifies the chat of a message.
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+// apiResponse is the envelope of every Bot API response.
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	Description string          `json:"description"`
+}
+
+// Run long-polls the Bot API and answers messages until ctx is done. The
+// replies in progress are completed before returning.
+func (b *Bot) Run(ctx context.Context) error {
+	if b.Token == "" {
+		return fmt.Errorf("telegram bot token is required")
+	}
+	if len(b.AllowedChats) == 0 && !b.AllowAllChats {
+		return fmt.Errorf("telegram allowed_chats is required, or allow_all_chats to answer every chat")
+	}
+
+	workers := b.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	slots := make(chan struct{}, workers)
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for ctx.Err() == nil {
+		updates, err := b.getUpdates(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			b.reportError(err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+
+		for _, update := range updates {
+			b.offset = update.UpdateID + 1
+			if update.Message == nil || !b.allowed(update.Message.Chat.ID) || !b.withinRate(update.Message.Chat.ID, time.Now()) {
+				continue
+			}
+			// Wait for a free worker.
+			select {
+			case <-ctx.Done():
+				return nil
+			case slots <- struct{}{}:
+			}
+			wg.Add(1)
+			go func(message Message) {
+				defer func() {
+					<-slots
+					wg.Done()
+				}()
+				b.handle(message)
+			}(*update.Message)
+		}
+	}
+	return nil
+}
+
+// allowed reports whether the bot answers the chat.
+func (b *Bot) allowed(chatID int64) bool {
+	if b.AllowAllChats {
+		return true
+	}
+	for _, id := range b.AllowedChats {
+		if id == chatID {
+			return true
+		}
+	}
+	return false
+}
+
+// withinRate reports whether a message of the chat received at now is within
+// MessagesPerMinute, and counts it if so.
+func (b *Bot) withinRate(chatID int64, now time.Time) bool {
+	limit := b.MessagesPerMinute
+	if limit <= 0 {
+		limit = defaultMessagesPerMinute
+	}
+	if b.received == nil {
+		b.received = make(map[int64][]time.Time)
+	}
+
+	recent := b.received[chatID][:0]
+	for _, at := range b.received[chatID] {
+		if now.Sub(at) < time.Minute {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) >= limit {
+		b.received[chatID] = recent
+		return false
+	}
+	b.received[chatID] = append(recent, now)
+	return true
+}
+
+// handle answers one message.
+func (b *Bot) handle(message Message) {
+	c := b.Chain
+	if c.Name == "" {
+		c = chain.Default
+	}
+	request, ok := ParseRequest(message.Text, c)
+	if !ok {
+		if command := strings.Fields(message.Text); len(command) > 0 && (command[0] == "/start" || command[0] == "/help") {
+			b.reply(message, Help(c))
+		}
+		return
+	}
+	if request.Problem != "" {
+		b.reply(message, request.Problem)
+		return
+	}
+
+	for _, address := range request.Addresses {
+		result := b.scan(request.Chain, address)
+		if b.OnScan != nil {
+			b.OnScan(request.Chain, address, result)
+		}
+		b.reply(message, Summary(request.Chain, address, result))
+	}
+}
+
+// scan scans a token with Scan or a multiscan.
+func (b *Bot) scan(c chain.Chain, address string) *multiscan.Result {
+	if b.Scan != nil {
+		return b.Scan(c, address)
+	}
+	return multiscan.ScanWithOptions(address, multiscan.Options{Chain: c, IncludeRaw: true})
+}
+
+// reply sends text to the chat of message, as a reply to it.
+func (b *Bot) reply(message Message, text string) {
+	err := b.call(context.Background(), "sendMessage", map[string]interface{}{
+		"chat_id":             message.Chat.ID,
+		"text":                text,
+		"reply_to_message_id": message.MessageID,
+	}, nil)
+	if err != nil {
+		b.reportError(err)
+	}
+}
+
+// pollTimeout returns PollTimeout or its default.
+func (b *Bot) pollTimeout() time.Duration {
+	if b.PollTimeout == 0 {
+		return defaultPollTimeout
+	}
+	return b.PollTimeout
+}
+
+// getUpdates waits for the updates following the last one handled.
+func (b *Bot) getUpdates(ctx context.Context) ([]Update, error) {
+	timeout := b.pollTimeout()
+
+	var updates []Update
+	err := b.call(ctx, "getUpdates", map[string]interface{}{
+		"offset":          b.offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}, &updates)
+	return updates, err
+}
+
+// call invokes a Bot API method and decodes its result into result.
+func (b *Bot) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("error marshaling %s request: %v", method, err)
+	}
+
+	baseURL := b.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	endpoint := fmt.Sprintf("%s/bot%s/%s", strings.TrimRight(baseURL, "/"), b.Token, method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating %s request: %v", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := b.Client
+	if client == nil {
+		client = &http.Client{Timeout: b.pollTimeout() + requestMargin}
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		// The URL holds the bot token; keep it out of the error.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("error calling %s: %v", method, err)
+	}
+	defer res.Body.Close()
+
+	var response apiResponse
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return fmt.Errorf("error decoding %s response (status %d): %v", method, res.StatusCode, err)
+	}
+	if !response.OK {
+		return fmt.Errorf("%s failed: %s", method, response.Description)
+	}
+	if result != nil {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("error decoding %s result: %v", method, err)
+		}
+	}
+	return nil
+}
+
+// reportError passes err to OnError.
+func (b *Bot) reportError(err error) {
+	if b.OnError != nil {
+		b.OnError(err)
+	}
+}
@@ -0,0 +1,78 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/s-Amine/token-scan/chain"
+)
+
+const testAddress = "0x6982508145454Ce325dDbE47a25d4ec3d2311933"
+
+func TestParseRequestChain(t *testing.T) {
+	tests := []struct {
+		text         string
+		defaultChain chain.Chain
+		want         chain.Chain
+	}{
+		{testAddress, chain.Default, chain.Default},
+		{testAddress, chain.BSC, chain.BSC},
+		{testAddress + " chain:bsc", chain.Default, chain.BSC},
+		{"chain:base " + testAddress, chain.BSC, chain.Base},
+		// Bare chain names are ordinary words.
+		{"is this on base? " + testAddress, chain.Default, chain.Default},
+		{testAddress + " arb bnb", chain.Default, chain.Default},
+	}
+	for _, tt := range tests {
+		request, ok := ParseRequest(tt.text, tt.defaultChain)
+		if !ok {
+			t.Errorf("%q: no request", tt.text)
+			continue
+		}
+		if request.Chain.Name != tt.want.Name || request.Problem != "" {
+			t.Errorf("%q: got chain %s (%q), want %s", tt.text, request.Chain.Name, request.Problem, tt.want.Name)
+		}
+	}
+}
+
+func TestRunRequiresAllowedChats(t *testing.T) {
+	bot := &Bot{Config: Config{Token: "token"}}
+	if err := bot.Run(context.Background()); err == nil {
+		t.Error("Run without allowed_chats or allow_all_chats: got no error")
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	bot := &Bot{Config: Config{AllowedChats: []int64{1}}}
+	if !bot.allowed(1) || bot.allowed(2) {
+		t.Errorf("allowed with allowed_chats [1]: got %v for 1 and %v for 2", bot.allowed(1), bot.allowed(2))
+	}
+	bot = &Bot{Config: Config{AllowAllChats: true}}
+	if !bot.allowed(2) {
+		t.Error("allowed with allow_all_chats: got false")
+	}
+}
+
+func TestWithinRate(t *testing.T) {
+	bot := &Bot{Config: Config{MessagesPerMinute: 2}}
+	start := time.Date(2025, 10, 19, 0, 0, 0, 0, time.UTC)
+
+	steps := []struct {
+		chat  int64
+		after time.Duration
+		want  bool
+	}{
+		{1, 0, true},
+		{1, time.Second, true},
+		{1, 2 * time.Second, false},
+		{2, 2 * time.Second, true},
+		{1, time.Minute, true},
+		{1, time.Minute + time.Second/2, false},
+	}
+	for i, step := range steps {
+		if got := bot.withinRate(step.chat, start.Add(step.after)); got != step.want {
+			t.Errorf("step %d: chat %d after %v: got %v, want %v", i, step.chat, step.after, got, step.want)
+		}
+	}
+}
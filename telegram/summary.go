@@ -0,0 +1,166 @@
+package telegram
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/token"
+)
+
+// maxAddresses bounds the tokens scanned for one message.
+const maxAddresses = 5
+
+// maxFactors bounds the risk factors listed in a summary.
+const maxFactors = 6
+
+// helpText answers /start and /help; the chain list is appended.
+const helpText = `Send a token address to get its risk verdict, optionally with a chain:
+0x... chain:bsc
+chain:base 0x...
+Chains: `
+
+var (
+	addressPattern = regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`)
+	chainPattern   = regexp.MustCompile(`(?i)\bchain[:=]\s*(\S+)`)
+)
+
+// Request is what a message asks for.
+type Request struct {
+	Chain     chain.Chain
+	Addresses []string
+	// Problem is the reply when the request cannot be served, e.g. for
+	// unknown chains.
+	Problem string
+}
+
+// Help returns the answer to /start and /help, naming c as the default chain.
+func Help(c chain.Chain) string {
+	names := make([]string, len(chain.All))
+	for i, known := range chain.All {
+		names[i] = known.Name
+		if known.Name == c.Name {
+			names[i] += " (default)"
+		}
+	}
+	return helpText + strings.Join(names, ", ") + "."
+}
+
+// ParseRequest finds token addresses and an optional chain in a message. The
+// chain is given as chain:<name or ID>; bare chain names are not matched since
+// words like "base" are common in chat. Messages without one are for c. It
+// reports false when the message has no address.
+func ParseRequest(text string, c chain.Chain) (Request, bool) {
+	request := Request{Chain: c}
+
+	seen := map[string]bool{}
+	for _, address := range addressPattern.FindAllString(text, -1) {
+		if key := strings.ToLower(address); !seen[key] {
+			seen[key] = true
+			request.Addresses = append(request.Addresses, address)
+		}
+	}
+	if len(request.Addresses) == 0 {
+		return request, false
+	}
+	if len(request.Addresses) > maxAddresses {
+		request.Problem = fmt.Sprintf("Send at most %d addresses per message.", maxAddresses)
+		return request, true
+	}
+
+	if match := chainPattern.FindStringSubmatch(text); match != nil {
+		c, ok := chain.Lookup(match[1])
+		if !ok {
+			request.Problem = fmt.Sprintf("Unknown chain %q.", match[1])
+		}
+		request.Chain = c
+	}
+	return request, true
+}
+
+// verdictMarks prefix the verdict line.
+var verdictMarks = map[string]string{
+	token.VerdictSafe:    "🟢",
+	token.VerdictCaution: "🟡",
+	token.VerdictDanger:  "🔴",
+	token.VerdictUnknown: "⚪",
+}
+
+// Summary formats a compact risk summary of a multiscan.
+func Summary(c chain.Chain, address string, result *multiscan.Result) string {
+	var b strings.Builder
+	info := result.Unified
+
+	if info.Risk != nil {
+		fmt.Fprintf(&b, "%s %s, score %d/100\n", verdictMarks[info.Risk.Level], strings.ToUpper(info.Risk.Level), info.Risk.Score)
+	}
+	name := info.TokenName
+	if info.TokenSymbol != "" {
+		name = strings.TrimSpace(fmt.Sprintf("%s (%s)", name, info.TokenSymbol))
+	}
+	if name == "" {
+		name = "Unknown token"
+	}
+	fmt.Fprintf(&b, "%s on %s\n%s\n", name, c.Name, address)
+
+	if info.Risk != nil && len(info.Risk.Factors) > 0 {
+		factors := append([]token.RiskFactor(nil), info.Risk.Factors...)
+		sort.SliceStable(factors, func(i, j int) bool {
+			return factors[i].Severity.Rank() > factors[j].Severity.Rank()
+		})
+		b.WriteString("\nRisk factors:\n")
+		for i, factor := range factors {
+			if i == maxFactors {
+				fmt.Fprintf(&b, "• and %d more\n", len(factors)-maxFactors)
+				break
+			}
+			fmt.Fprintf(&b, "• %s: %s\n", factor.Severity, factor.Reason)
+		}
+	}
+
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "Taxes: buy %s, sell %s\n", formatTax(info.BuyTax), formatTax(info.SellTax))
+	if info.Liquidity != nil {
+		fmt.Fprintf(&b, "Liquidity: $%s", formatThousands(info.Liquidity.LiquidityUSD))
+		if info.Liquidity.AgeHours > 0 {
+			fmt.Fprintf(&b, ", pair age %.1f days", info.Liquidity.AgeHours/24)
+		}
+		b.WriteString("\n")
+	}
+	if result.Raw != nil && len(result.Raw.Errors) > 0 {
+		providers := make([]string, 0, len(result.Raw.Errors))
+		for provider := range result.Raw.Errors {
+			providers = append(providers, provider)
+		}
+		sort.Strings(providers)
+		fmt.Fprintf(&b, "Unavailable: %s\n", strings.Join(providers, ", "))
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatTax formats a tax fraction as a percentage.
+func formatTax(value string) string {
+	tax, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return "unknown"
+	}
+	return strconv.FormatFloat(tax*100, 'f', -1, 64) + "%"
+}
+
+// formatThousands formats an amount rounded to units with thousands separators.
+func formatThousands(amount float64) string {
+	digits := strconv.FormatFloat(amount, 'f', 0, 64)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 && digits[i-1] != '-' {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
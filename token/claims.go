@@ -0,0 +1,127 @@
+package token
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/models"
+	"github.com/s-Amine/token-scan/scanners/ishoneypot"
+	"github.com/s-Amine/token-scan/scanners/quickintel"
+)
+
+// claim is what a single provider reports about the control of a contract.
+// Nil pointers mean the provider does not report the value.
+type claim struct {
+	source             string
+	creator            string
+	owner              *string
+	renounced          *bool
+	hiddenOwner        *bool
+	isProxy            *bool
+	hasProxyCalls      *bool
+	implementation     string
+	onlyOwnerFunctions []string
+}
+
+// claimFromGoPlus extracts the claim of a GoPlus result.
+func claimFromGoPlus(r models.ResponseWrapperTokenSecurityResultAnon) *claim {
+	if r.TokenName == "" && r.CreatorAddress == "" && r.OwnerAddress == "" {
+		return nil
+	}
+	owner := r.OwnerAddress
+	renounced := isRenouncedOwner(owner)
+	return &claim{
+		source:      SourceGoPlus,
+		creator:     r.CreatorAddress,
+		owner:       &owner,
+		renounced:   &renounced,
+		hiddenOwner: parseOptionalBool(r.HiddenOwner),
+		isProxy:     parseOptionalBool(r.IsProxy),
+	}
+}
+
+// claimFromQuickIntel extracts the claim of a QuickIntel audit.
+func claimFromQuickIntel(response quickintel.QuickIntelResponse) *claim {
+	audit := response.QuickiAudit
+	if audit.ContractAddress == "" && response.TokenDetails.TokenName == "" {
+		return nil
+	}
+	owner := audit.ContractOwner
+	if owner == "" {
+		owner = response.TokenDetails.TokenOwner
+	}
+	renounced := audit.ContractRenounced || isRenouncedOwner(owner)
+	hiddenOwner := audit.HiddenOwner
+	isProxy := audit.IsProxy
+	return &claim{
+		source:             SourceQuickIntel,
+		creator:            audit.ContractCreator,
+		owner:              &owner,
+		renounced:          &renounced,
+		hiddenOwner:        &hiddenOwner,
+		isProxy:            &isProxy,
+		implementation:     audit.ProxyImplementation,
+		onlyOwnerFunctions: audit.OnlyOwnerFunctions,
+	}
+}
+
+// claimFromHoneypot extracts the claim of a honeypot.is response.
+// honeypot.is reports proxy usage but nothing about ownership.
+func claimFromHoneypot(response ishoneypot.HoneypotResponse) *claim {
+	if response.Token.Address == "" && response.Token.Name == "" {
+		return nil
+	}
+	isProxy := response.ContractCode.IsProxy
+	hasProxyCalls := response.ContractCode.HasProxyCalls
+	return &claim{
+		source:        SourceHoneypot,
+		isProxy:       &isProxy,
+		hasProxyCalls: &hasProxyCalls,
+	}
+}
+
+// collectClaims gathers the claims of several token infos.
+func collectClaims(infos ...*TokenInfo) []*claim {
+	var claims []*claim
+	for _, info := range infos {
+		claims = append(claims, info.claims...)
+	}
+	return claims
+}
+
+// Disagreement represents a field for which providers reported different values.
+type Disagreement struct {
+	Field string `json:"field"`
+	// Values maps each provider to the value it reported.
+	Values map[string]string `json:"values"`
+}
+
+// appendDisagreement appends a disagreement on field when values holds more
+// than one distinct value.
+func appendDisagreement(disagreements []Disagreement, field string, values map[string]string) []Disagreement {
+	distinct := make(map[string]bool)
+	for _, value := range values {
+		distinct[value] = true
+	}
+	if len(distinct) > 1 {
+		disagreements = append(disagreements, Disagreement{Field: field, Values: values})
+	}
+	return disagreements
+}
+
+// isRenouncedOwner reports whether owner is empty or a burn address.
+func isRenouncedOwner(owner string) bool {
+	return owner == "" || burnAddresses[strings.ToLower(owner)]
+}
+
+// parseOptionalBool parses a GoPlus "0"/"1" flag, returning nil when it is absent.
+func parseOptionalBool(value string) *bool {
+	if value == "" {
+		return nil
+	}
+	val, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil
+	}
+	return &val
+}
@@ -0,0 +1,142 @@
+package token
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/models"
+)
+
+// topHolderCount is the number of largest holders summed into TopHoldersShare.
+const topHolderCount = 10
+
+// burnAddresses are the addresses tokens are sent to in order to burn them.
+var burnAddresses = map[string]bool{
+	"0x0000000000000000000000000000000000000000": true,
+	"0x000000000000000000000000000000000000dead": true,
+	"0xdead000000000000000042069420694206942069": true,
+}
+
+// Concentration represents how the token supply and its liquidity are distributed.
+// Shares are fractions of the total supply, from 0 to 1.
+type Concentration struct {
+	HolderCount int `json:"holder_count,omitempty"`
+	// TopHoldersShare is the share held by the ten largest holders, excluding
+	// DEX pairs, burn addresses and locked balances.
+	TopHoldersShare float64  `json:"top_holders_share"`
+	TopHolders      []Holder `json:"top_holders,omitempty"`
+	CreatorAddress  string   `json:"creator_address,omitempty"`
+	CreatorShare    float64  `json:"creator_share"`
+	OwnerAddress    string   `json:"owner_address,omitempty"`
+	OwnerShare      float64  `json:"owner_share"`
+	LPHolderCount   int      `json:"lp_holder_count,omitempty"`
+	LPLockedShare   float64  `json:"lp_locked_share"`
+	LPBurnedShare   float64  `json:"lp_burned_share"`
+	LPHolders       []Holder `json:"lp_holders,omitempty"`
+}
+
+// Holder represents a token or LP token holder.
+type Holder struct {
+	Address    string  `json:"address"`
+	Tag        string  `json:"tag,omitempty"`
+	Share      float64 `json:"share"`
+	IsContract bool    `json:"is_contract,omitempty"`
+	IsLocked   bool    `json:"is_locked,omitempty"`
+	IsBurn     bool    `json:"is_burn,omitempty"`
+	IsPair     bool    `json:"is_pair,omitempty"`
+}
+
+// newConcentration computes the holder and LP metrics of a GoPlus result.
+// It returns nil when GoPlus returned no holder information.
+func newConcentration(r models.ResponseWrapperTokenSecurityResultAnon) *Concentration {
+	if len(r.Holders) == 0 && len(r.LpHolders) == 0 && r.CreatorAddress == "" && r.OwnerAddress == "" {
+		return nil
+	}
+
+	c := &Concentration{
+		HolderCount:    parseCount(r.HolderCount),
+		CreatorAddress: r.CreatorAddress,
+		OwnerAddress:   r.OwnerAddress,
+		LPHolderCount:  parseCount(r.LpHolderCount),
+	}
+	c.CreatorShare = parseShare(r.CreatorPercent)
+	c.OwnerShare = parseShare(r.OwnerPercent)
+
+	pairs := make(map[string]bool)
+	for _, dex := range r.Dex {
+		if dex != nil {
+			pairs[strings.ToLower(dex.Pair)] = true
+		}
+	}
+
+	for _, item := range r.Holders {
+		if item == nil {
+			continue
+		}
+		holder := newHolder(item.Address, item.Tag, item.Percent, item.IsContract, item.IsLocked)
+		holder.IsPair = pairs[strings.ToLower(item.Address)]
+		c.TopHolders = append(c.TopHolders, holder)
+	}
+	sort.SliceStable(c.TopHolders, func(i, j int) bool {
+		return c.TopHolders[i].Share > c.TopHolders[j].Share
+	})
+	counted := 0
+	for _, holder := range c.TopHolders {
+		if counted == topHolderCount {
+			break
+		}
+		if holder.IsBurn || holder.IsLocked || holder.IsPair {
+			continue
+		}
+		c.TopHoldersShare += holder.Share
+		counted++
+	}
+
+	for _, item := range r.LpHolders {
+		if item == nil {
+			continue
+		}
+		holder := newHolder(item.Address, item.Tag, item.Percent, item.IsContract, item.IsLocked)
+		switch {
+		case holder.IsBurn:
+			c.LPBurnedShare += holder.Share
+		case holder.IsLocked:
+			c.LPLockedShare += holder.Share
+		}
+		c.LPHolders = append(c.LPHolders, holder)
+	}
+
+	return c
+}
+
+// newHolder converts a GoPlus holder entry.
+func newHolder(address, tag, percent string, isContract, isLocked int32) Holder {
+	return Holder{
+		Address:    address,
+		Tag:        tag,
+		Share:      parseShare(percent),
+		IsContract: isContract == 1,
+		IsLocked:   isLocked == 1,
+		IsBurn:     burnAddresses[strings.ToLower(address)],
+	}
+}
+
+// parseShare parses a GoPlus holding percentage, which is a fraction of the
+// supply such as "0.05", treating empty or malformed values as zero.
+func parseShare(value string) float64 {
+	share, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0
+	}
+	return share
+}
+
+// parseCount parses a holder count, treating empty or malformed values as zero.
+func parseCount(value string) int {
+	count, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0
+	}
+	return count
+}
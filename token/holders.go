@@ -0,0 +1,62 @@
+package token
+
+import "github.com/s-Amine/token-scan/scanners/ishoneypot"
+
+// HolderAnalysis represents the result of simulating sells for the token holders.
+type HolderAnalysis struct {
+	Holders         int          `json:"holders"`
+	Successful      int          `json:"successful"`
+	Failed          int          `json:"failed"`
+	Siphoned        int          `json:"siphoned"`
+	AverageTax      float64      `json:"average_tax"`
+	HighestTax      float64      `json:"highest_tax"`
+	HighTaxWallets  int          `json:"high_tax_wallets"`
+	AverageGas      float64      `json:"average_gas"`
+	TaxDistribution []TaxBracket `json:"tax_distribution,omitempty"`
+	SnipersSuccess  int          `json:"snipers_success"`
+	SnipersFailed   int          `json:"snipers_failed"`
+	// FailedSellShare is the share of simulated holders unable to sell, from 0 to 1.
+	FailedSellShare float64 `json:"failed_sell_share"`
+	// SiphonedDetected reports whether tokens were siphoned from any holder wallet.
+	SiphonedDetected bool `json:"siphoned_detected"`
+}
+
+// TaxBracket represents the number of holders paying a given sell tax.
+type TaxBracket struct {
+	Tax   float64 `json:"tax"`
+	Count int     `json:"count"`
+}
+
+// newHolderAnalysis converts the honeypot.is holder analysis.
+// It returns nil when the response carries no holder analysis.
+func newHolderAnalysis(response ishoneypot.HoneypotResponse) *HolderAnalysis {
+	h := response.HolderAnalysis
+	analysis := &HolderAnalysis{
+		Holders:        int(h.Holders),
+		Successful:     int(h.Successful),
+		Failed:         int(h.Failed),
+		Siphoned:       int(h.Siphoned),
+		AverageTax:     float64(h.AverageTax),
+		HighestTax:     float64(h.HighestTax),
+		HighTaxWallets: int(h.HighTaxWallets),
+		AverageGas:     float64(h.AverageGas),
+		SnipersSuccess: int(h.SnipersSuccess),
+		SnipersFailed:  int(h.SnipersFailed),
+	}
+	if analysis.Holders == 0 && analysis.Successful == 0 && analysis.Failed == 0 {
+		return nil
+	}
+	for _, bracket := range h.TaxDistribution {
+		analysis.TaxDistribution = append(analysis.TaxDistribution, TaxBracket{
+			Tax:   float64(bracket.Tax),
+			Count: int(bracket.Count),
+		})
+	}
+
+	if simulated := analysis.Successful + analysis.Failed; simulated > 0 {
+		analysis.FailedSellShare = float64(analysis.Failed) / float64(simulated)
+	}
+	analysis.SiphonedDetected = analysis.Siphoned > 0
+
+	return analysis
+}
@@ -0,0 +1,63 @@
+package token
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/s-Amine/token-scan/scanners/ishoneypot"
+)
+
+// Liquidity represents the main trading pair of the token.
+type Liquidity struct {
+	PairAddress string `json:"pair_address"`
+	PairName    string `json:"pair_name,omitempty"`
+	// Dex is the pair type reported by the provider, such as UniswapV2.
+	Dex          string     `json:"dex,omitempty"`
+	Router       string     `json:"router,omitempty"`
+	QuoteToken   string     `json:"quote_token,omitempty"`
+	QuoteSymbol  string     `json:"quote_symbol,omitempty"`
+	TokenReserve *big.Int   `json:"token_reserve,omitempty"`
+	QuoteReserve *big.Int   `json:"quote_reserve,omitempty"`
+	LiquidityUSD float64    `json:"liquidity_usd"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	// AgeHours is the age of the pair when it was scanned.
+	AgeHours float64 `json:"age_hours,omitempty"`
+}
+
+// newLiquidity converts the honeypot.is pair information.
+// It returns nil when the response carries no pair.
+func newLiquidity(response ishoneypot.HoneypotResponse, now time.Time) *Liquidity {
+	pair := response.Pair
+	if pair.PairAddress == "" {
+		return nil
+	}
+
+	liquidity := &Liquidity{
+		PairAddress:  pair.PairAddress,
+		PairName:     pair.PairName,
+		Dex:          pair.Type,
+		Router:       pair.Router,
+		QuoteToken:   response.WithToken.Address,
+		QuoteSymbol:  response.WithToken.Symbol,
+		LiquidityUSD: float64(pair.Liquidity),
+	}
+	if liquidity.Router == "" {
+		liquidity.Router = response.Router
+	}
+
+	// Order the reserves so that the token side comes first.
+	reserve0, reserve1 := pair.Reserves0.Int(), pair.Reserves1.Int()
+	if strings.EqualFold(pair.Token1, response.Token.Address) {
+		reserve0, reserve1 = reserve1, reserve0
+	}
+	liquidity.TokenReserve, liquidity.QuoteReserve = reserve0, reserve1
+
+	if pair.CreatedAtTimestamp > 0 {
+		createdAt := time.Unix(int64(pair.CreatedAtTimestamp), 0).UTC()
+		liquidity.CreatedAt = &createdAt
+		liquidity.AgeHours = now.Sub(createdAt).Hours()
+	}
+
+	return liquidity
+}
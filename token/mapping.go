@@ -0,0 +1,341 @@
+package token
+
+import (
+	"strconv"
+
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/models"
+	"github.com/s-Amine/token-scan/decode"
+	"github.com/s-Amine/token-scan/scanners/ishoneypot"
+	"github.com/s-Amine/token-scan/scanners/quickintel"
+)
+
+// FieldSemantics documents the meaning of every unified scalar field of
+// TokenInfo. Capability flags follow the GoPlus definitions: they report that
+// the contract contains the mechanism, not that it is currently in use.
+var FieldSemantics = map[string]string{
+	"token_name":                   "token name",
+	"token_symbol":                 "token symbol",
+	"decimals":                     "token decimals",
+	"uniswapv2_pair":               "address of the main trading pair",
+	"is_honeypot":                  "a buy and sell simulation or provider analysis found the token cannot be sold",
+	"is_open_source":               "contract source code is verified",
+	"is_whitelisted":               "GoPlus reports a whitelist in the contract, exempting some addresses from trading limits",
+	"can_whitelist":                "owner can add addresses to a whitelist",
+	"can_take_back_ownership":      "ownership can be reclaimed after being renounced",
+	"owner_change_balance":         "owner can change holder balances",
+	"cannot_buy":                   "token cannot be bought",
+	"cannot_sell_all":              "holders cannot sell their whole balance at once",
+	"is_mintable":                  "contract contains a mint function",
+	"hidden_owner":                 "contract keeps owner privileges outside the visible owner",
+	"transfer_pausable":            "contract contains a function to pause trading",
+	"is_blacklisted":               "contract contains a blacklist mechanism",
+	"buy_tax":                      "buy tax as a fraction, e.g. 0.05 for 5%",
+	"sell_tax":                     "sell tax as a fraction, e.g. 0.05 for 5%",
+	"external_call":                "contract calls external contracts in its transfer logic",
+	"trading_cooldown":             "contract enforces a cooldown between trades",
+	"personal_slippage_modifiable": "owner can set the tax of individual addresses",
+	"honeypot_reason":              "why the token was found to be a honeypot",
+	"transfer_tax":                 "tax on transfers between wallets as a fraction",
+	"post_reenable_buy_tax":        "buy tax once trading is re-enabled, as a fraction",
+	"post_reenable_sell_tax":       "sell tax once trading is re-enabled, as a fraction",
+	"max_transaction":              "largest amount of one transaction, in token units",
+	"max_transaction_share":        "largest amount of one transaction as a fraction of the supply",
+	"max_wallet":                   "largest balance of one wallet, in token units",
+	"max_wallet_share":             "largest balance of one wallet as a fraction of the supply",
+	"burned_supply":                "burned supply, in token units",
+	"lp_supply":                    "supply of the main pair's LP token",
+	"lp_burned_share":              "share of the LP supply burned, as a fraction",
+	"lp_locked_share":              "share of the LP supply locked, as a fraction",
+	"lp_locked_until":              "Unix time the LP lock expires",
+	"lp_holder_count":              "number of LP token holders",
+	"holder_count":                 "number of token holders",
+	"price_impact":                 "price impact of a test trade as a fraction",
+}
+
+// FieldMapping describes which provider field feeds a unified TokenInfo field.
+type FieldMapping struct {
+	Provider string `json:"provider"`
+	// Field is the JSON name of the unified field.
+	Field string `json:"field"`
+	// SourceField is the JSON path of the provider field.
+	SourceField string `json:"source_field"`
+	// Percent is set when the provider field is a percentage, mapped to a
+	// fraction.
+	Percent bool `json:"percent,omitempty"`
+}
+
+// mapping copies one provider field of a response of type R into TokenInfo.
+type mapping[R any] struct {
+	field   string
+	source  string
+	apply   func(r *R, t *TokenInfo)
+	percent bool
+}
+
+// applyMappings applies every mapping to t.
+func applyMappings[R any](mappings []mapping[R], r *R, t *TokenInfo) {
+	for _, m := range mappings {
+		m.apply(r, t)
+	}
+}
+
+// reportMappings records the unified fields of mappings as reported by a
+// provider that answered.
+func reportMappings[R any](mappings []mapping[R], t *TokenInfo) {
+	for _, m := range mappings {
+		t.setReported(m.field)
+	}
+}
+
+// describeMappings converts mappings into their exported description.
+func describeMappings[R any](provider string, mappings []mapping[R]) []FieldMapping {
+	described := make([]FieldMapping, 0, len(mappings))
+	for _, m := range mappings {
+		described = append(described, FieldMapping{Provider: provider, Field: m.field, SourceField: m.source, Percent: m.percent})
+	}
+	return described
+}
+
+// FieldMappings returns the field mappings of every provider.
+func FieldMappings() []FieldMapping {
+	var mappings []FieldMapping
+	mappings = append(mappings, describeMappings(SourceGoPlus, goPlusMappings)...)
+	mappings = append(mappings, describeMappings(SourceHoneypot, honeypotMappings)...)
+	mappings = append(mappings, describeMappings(SourceQuickIntel, quickIntelMappings)...)
+	mappings = append(mappings, describeMappings(SourceQuickIntel, quickIntelPremiumMappings)...)
+	return mappings
+}
+
+// goPlusString maps a GoPlus string to a string field. GoPlus leaves out the
+// fields it knows nothing about, so only non-empty values are reported.
+func goPlusString(field, source string, get func(r *models.ResponseWrapperTokenSecurityResultAnon) string, dst func(t *TokenInfo) *string) mapping[models.ResponseWrapperTokenSecurityResultAnon] {
+	return mapping[models.ResponseWrapperTokenSecurityResultAnon]{
+		field:  field,
+		source: source,
+		apply: func(r *models.ResponseWrapperTokenSecurityResultAnon, t *TokenInfo) {
+			if value := get(r); value != "" {
+				*dst(t) = value
+				t.setReported(field)
+			}
+		},
+	}
+}
+
+// goPlusFlag maps a GoPlus "0"/"1" flag to a boolean field.
+func goPlusFlag(field, source string, get func(r *models.ResponseWrapperTokenSecurityResultAnon) string, dst func(t *TokenInfo) *bool) mapping[models.ResponseWrapperTokenSecurityResultAnon] {
+	return mapping[models.ResponseWrapperTokenSecurityResultAnon]{
+		field:  field,
+		source: source,
+		apply: func(r *models.ResponseWrapperTokenSecurityResultAnon, t *TokenInfo) {
+			value := get(r)
+			t.setBoolField(value, dst(t))
+			if value != "" {
+				t.setReported(field)
+			}
+		},
+	}
+}
+
+// goPlusMappings maps the GoPlus token security result.
+var goPlusMappings = []mapping[models.ResponseWrapperTokenSecurityResultAnon]{
+	goPlusString("token_name", "token_name",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.TokenName },
+		func(t *TokenInfo) *string { return &t.TokenName }),
+	goPlusString("token_symbol", "token_symbol",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.TokenSymbol },
+		func(t *TokenInfo) *string { return &t.TokenSymbol }),
+	goPlusString("buy_tax", "buy_tax",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.BuyTax },
+		func(t *TokenInfo) *string { return &t.BuyTax }),
+	goPlusString("sell_tax", "sell_tax",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.SellTax },
+		func(t *TokenInfo) *string { return &t.SellTax }),
+	goPlusFlag("can_take_back_ownership", "can_take_back_ownership",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.CanTakeBackOwnership },
+		func(t *TokenInfo) *bool { return &t.CanTakeBackOwnership }),
+	goPlusFlag("cannot_buy", "cannot_buy",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.CannotBuy },
+		func(t *TokenInfo) *bool { return &t.CannotBuy }),
+	goPlusFlag("cannot_sell_all", "cannot_sell_all",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.CannotSellAll },
+		func(t *TokenInfo) *bool { return &t.CannotSellAll }),
+	goPlusFlag("external_call", "external_call",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.ExternalCall },
+		func(t *TokenInfo) *bool { return &t.ExternalCall }),
+	goPlusFlag("hidden_owner", "hidden_owner",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.HiddenOwner },
+		func(t *TokenInfo) *bool { return &t.HiddenOwner }),
+	goPlusFlag("is_blacklisted", "is_blacklisted",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.IsBlacklisted },
+		func(t *TokenInfo) *bool { return &t.IsBlacklisted }),
+	goPlusFlag("is_honeypot", "is_honeypot",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.IsHoneypot },
+		func(t *TokenInfo) *bool { return &t.IsHoneypot }),
+	goPlusFlag("is_mintable", "is_mintable",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.IsMintable },
+		func(t *TokenInfo) *bool { return &t.IsMintable }),
+	goPlusFlag("is_open_source", "is_open_source",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.IsOpenSource },
+		func(t *TokenInfo) *bool { return &t.IsOpenSource }),
+	goPlusFlag("is_whitelisted", "is_whitelisted",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.IsWhitelisted },
+		func(t *TokenInfo) *bool { return &t.IsWhitelisted }),
+	goPlusFlag("owner_change_balance", "owner_change_balance",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.OwnerChangeBalance },
+		func(t *TokenInfo) *bool { return &t.OwnerChangeBalance }),
+	goPlusFlag("trading_cooldown", "trading_cooldown",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.TradingCooldown },
+		func(t *TokenInfo) *bool { return &t.TradingCooldown }),
+	goPlusFlag("transfer_pausable", "transfer_pausable",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.TransferPausable },
+		func(t *TokenInfo) *bool { return &t.TransferPausable }),
+	goPlusFlag("personal_slippage_modifiable", "personal_slippage_modifiable",
+		func(r *models.ResponseWrapperTokenSecurityResultAnon) string { return r.PersonalSlippageModifiable },
+		func(t *TokenInfo) *bool { return &t.PersonalSlippageModifiable }),
+}
+
+// honeypotMappings maps the honeypot.is response.
+var honeypotMappings = []mapping[ishoneypot.HoneypotResponse]{
+	{field: "token_name", source: "token.name", apply: func(r *ishoneypot.HoneypotResponse, t *TokenInfo) { t.TokenName = r.Token.Name }},
+	{field: "token_symbol", source: "token.symbol", apply: func(r *ishoneypot.HoneypotResponse, t *TokenInfo) { t.TokenSymbol = r.Token.Symbol }},
+	{field: "decimals", source: "token.decimals", apply: func(r *ishoneypot.HoneypotResponse, t *TokenInfo) { t.Decimals = int(r.Token.Decimals) }},
+	{field: "uniswapv2_pair", source: "pair.address", apply: func(r *ishoneypot.HoneypotResponse, t *TokenInfo) { t.UniswapV2Pair = r.Pair.PairAddress }},
+	{field: "is_honeypot", source: "honeypotResult.isHoneypot", apply: func(r *ishoneypot.HoneypotResponse, t *TokenInfo) { t.IsHoneypot = r.HoneypotResult.IsHoneypot }},
+	{field: "is_open_source", source: "contractCode.openSource", apply: func(r *ishoneypot.HoneypotResponse, t *TokenInfo) { t.IsOpenSource = r.ContractCode.OpenSource }},
+}
+
+// quickIntelMappings maps the QuickIntel audit. QuickIntel capability flags
+// (can_*) feed the matching unified capability flags; is_honeypot only comes
+// from the dynamic honeypot check, and a hidden owner is reported as such.
+var quickIntelMappings = []mapping[quickintel.QuickIntelResponse]{
+	{field: "token_name", source: "tokenDetails.tokenName", apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) { t.TokenName = r.TokenDetails.TokenName }},
+	{field: "token_symbol", source: "tokenDetails.tokenSymbol", apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) { t.TokenSymbol = r.TokenDetails.TokenSymbol }},
+	{field: "decimals", source: "tokenDetails.tokenDecimals", apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) { t.Decimals = int(r.TokenDetails.TokenDecimals) }},
+	{field: "is_honeypot", source: "tokenDynamicDetails.is_Honeypot", apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) { t.IsHoneypot = r.TokenDynamicDetails.IsHoneypot }},
+	{field: "hidden_owner", source: "quickiAudit.hidden_Owner", apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) { t.HiddenOwner = r.QuickiAudit.HiddenOwner }},
+	{field: "can_whitelist", source: "quickiAudit.can_Whitelist", apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) { t.CanWhitelist = r.QuickiAudit.CanWhitelist }},
+	{field: "is_mintable", source: "quickiAudit.can_Mint", apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) { t.IsMintable = r.QuickiAudit.CanMint }},
+	{field: "transfer_pausable", source: "quickiAudit.can_Pause_Trading", apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) {
+		t.TransferPausable = r.QuickiAudit.CanPauseTrading
+	}},
+	{field: "is_blacklisted", source: "quickiAudit.can_Blacklist", apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) { t.IsBlacklisted = r.QuickiAudit.CanBlacklist }},
+	{field: "external_call", source: "quickiAudit.has_External_Contract_Risk", apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) {
+		t.ExternalCall = r.QuickiAudit.HasExternalContractRisk
+	}},
+	{field: "trading_cooldown", source: "quickiAudit.has_Trading_Cooldown", apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) {
+		t.TradingCooldown = r.QuickiAudit.HasTradingCooldown
+	}},
+}
+
+// quickIntelText maps a premium QuickIntel string, reported when set.
+func quickIntelText(field, source string, get func(r *quickintel.QuickIntelResponse) string, dst func(t *TokenInfo) *string) mapping[quickintel.QuickIntelResponse] {
+	return mapping[quickintel.QuickIntelResponse]{
+		field:  field,
+		source: source,
+		apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) {
+			if value := get(r); value != "" {
+				*dst(t) = value
+				t.setReported(field)
+			}
+		},
+	}
+}
+
+// quickIntelAmount maps a premium QuickIntel integer amount to its decimal
+// string, reported when set.
+func quickIntelAmount(field, source string, get func(r *quickintel.QuickIntelResponse) decode.BigInt, dst func(t *TokenInfo) *string) mapping[quickintel.QuickIntelResponse] {
+	return quickIntelText(field, source, func(r *quickintel.QuickIntelResponse) string { return get(r).String() }, dst)
+}
+
+// quickIntelCount maps a premium QuickIntel integer, reported when not null.
+func quickIntelCount(field, source string, get func(r *quickintel.QuickIntelResponse) *decode.Int, dst func(t *TokenInfo) *int) mapping[quickintel.QuickIntelResponse] {
+	return mapping[quickintel.QuickIntelResponse]{
+		field:  field,
+		source: source,
+		apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) {
+			if value := get(r); value != nil {
+				*dst(t) = int(*value)
+				t.setReported(field)
+			}
+		},
+	}
+}
+
+// quickIntelPercent maps a premium QuickIntel percentage to a fraction
+// string, such as "0.05" for 5, reported when not null.
+func quickIntelPercent(field, source string, get func(r *quickintel.QuickIntelResponse) *decode.Float, dst func(t *TokenInfo) *string) mapping[quickintel.QuickIntelResponse] {
+	return mapping[quickintel.QuickIntelResponse]{
+		field:  field,
+		source: source,
+		apply: func(r *quickintel.QuickIntelResponse, t *TokenInfo) {
+			if value := get(r); value != nil {
+				*dst(t) = strconv.FormatFloat(float64(*value)/100, 'f', -1, 64)
+				t.setReported(field)
+			}
+		},
+		percent: true,
+	}
+}
+
+// quickIntelPremiumMappings map the tokenDynamicDetails fields only the
+// premium tier returns. The basic tier returns them as null, so each field is
+// only set and reported when present.
+var quickIntelPremiumMappings = []mapping[quickintel.QuickIntelResponse]{
+	quickIntelText("honeypot_reason", "tokenDynamicDetails.honeypot_Reason",
+		func(r *quickintel.QuickIntelResponse) string { return r.TokenDynamicDetails.HoneypotReason },
+		func(t *TokenInfo) *string { return &t.HoneypotReason }),
+	quickIntelPercent("buy_tax", "tokenDynamicDetails.buy_Tax",
+		func(r *quickintel.QuickIntelResponse) *decode.Float { return r.TokenDynamicDetails.BuyTax },
+		func(t *TokenInfo) *string { return &t.BuyTax }),
+	quickIntelPercent("sell_tax", "tokenDynamicDetails.sell_Tax",
+		func(r *quickintel.QuickIntelResponse) *decode.Float { return r.TokenDynamicDetails.SellTax },
+		func(t *TokenInfo) *string { return &t.SellTax }),
+	quickIntelPercent("transfer_tax", "tokenDynamicDetails.transfer_Tax",
+		func(r *quickintel.QuickIntelResponse) *decode.Float { return r.TokenDynamicDetails.TransferTax },
+		func(t *TokenInfo) *string { return &t.TransferTax }),
+	quickIntelPercent("post_reenable_buy_tax", "tokenDynamicDetails.post_Reenable_Buy_Tax",
+		func(r *quickintel.QuickIntelResponse) *decode.Float { return r.TokenDynamicDetails.PostReenableBuyTax },
+		func(t *TokenInfo) *string { return &t.PostReenableBuyTax }),
+	quickIntelPercent("post_reenable_sell_tax", "tokenDynamicDetails.post_Reenable_Sell_Tax",
+		func(r *quickintel.QuickIntelResponse) *decode.Float { return r.TokenDynamicDetails.PostReenableSellTax },
+		func(t *TokenInfo) *string { return &t.PostReenableSellTax }),
+	quickIntelAmount("max_transaction", "tokenDynamicDetails.max_Transaction",
+		func(r *quickintel.QuickIntelResponse) decode.BigInt { return r.TokenDynamicDetails.MaxTransaction },
+		func(t *TokenInfo) *string { return &t.MaxTransaction }),
+	quickIntelPercent("max_transaction_share", "tokenDynamicDetails.max_Transaction_Percent",
+		func(r *quickintel.QuickIntelResponse) *decode.Float { return r.TokenDynamicDetails.MaxTransactionPct },
+		func(t *TokenInfo) *string { return &t.MaxTransactionShare }),
+	quickIntelAmount("max_wallet", "tokenDynamicDetails.max_Wallet",
+		func(r *quickintel.QuickIntelResponse) decode.BigInt { return r.TokenDynamicDetails.MaxWallet },
+		func(t *TokenInfo) *string { return &t.MaxWallet }),
+	quickIntelPercent("max_wallet_share", "tokenDynamicDetails.max_Wallet_Percent",
+		func(r *quickintel.QuickIntelResponse) *decode.Float { return r.TokenDynamicDetails.MaxWalletPct },
+		func(t *TokenInfo) *string { return &t.MaxWalletShare }),
+	quickIntelAmount("burned_supply", "tokenDynamicDetails.token_Supply_Burned",
+		func(r *quickintel.QuickIntelResponse) decode.BigInt { return r.TokenDynamicDetails.TokenSupplyBurned },
+		func(t *TokenInfo) *string { return &t.BurnedSupply }),
+	quickIntelText("uniswapv2_pair", "tokenDynamicDetails.lp_Pair",
+		func(r *quickintel.QuickIntelResponse) string { return r.TokenDynamicDetails.LpPair },
+		func(t *TokenInfo) *string { return &t.UniswapV2Pair }),
+	quickIntelAmount("lp_supply", "tokenDynamicDetails.lp_Supply",
+		func(r *quickintel.QuickIntelResponse) decode.BigInt { return r.TokenDynamicDetails.LpSupply },
+		func(t *TokenInfo) *string { return &t.LPSupply }),
+	quickIntelPercent("lp_burned_share", "tokenDynamicDetails.lp_Burned_Percent",
+		func(r *quickintel.QuickIntelResponse) *decode.Float { return r.TokenDynamicDetails.LpBurnedPercent },
+		func(t *TokenInfo) *string { return &t.LPBurnedShare }),
+	quickIntelPercent("lp_locked_share", "tokenDynamicDetails.lp_Locked_Percent",
+		func(r *quickintel.QuickIntelResponse) *decode.Float { return r.TokenDynamicDetails.LpLockedPercent },
+		func(t *TokenInfo) *string { return &t.LPLockedShare }),
+	quickIntelCount("lp_locked_until", "tokenDynamicDetails.lp_Locked_Until",
+		func(r *quickintel.QuickIntelResponse) *decode.Int { return r.TokenDynamicDetails.LpLockedUntil },
+		func(t *TokenInfo) *int { return &t.LPLockedUntil }),
+	quickIntelCount("lp_holder_count", "tokenDynamicDetails.lp_Holder_Count",
+		func(r *quickintel.QuickIntelResponse) *decode.Int { return r.TokenDynamicDetails.LpHolderCount },
+		func(t *TokenInfo) *int { return &t.LPHolderCount }),
+	quickIntelCount("holder_count", "tokenDynamicDetails.token_Holder_Count",
+		func(r *quickintel.QuickIntelResponse) *decode.Int { return r.TokenDynamicDetails.TokenHolderCount },
+		func(t *TokenInfo) *int { return &t.HolderCount }),
+	quickIntelPercent("price_impact", "tokenDynamicDetails.price_Impact",
+		func(r *quickintel.QuickIntelResponse) *decode.Float { return r.TokenDynamicDetails.PriceImpactPercent },
+		func(t *TokenInfo) *string { return &t.PriceImpact }),
+}
@@ -0,0 +1,351 @@
+package token
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/models"
+	"github.com/s-Amine/token-scan/decode"
+	"github.com/s-Amine/token-scan/scanners/ishoneypot"
+	"github.com/s-Amine/token-scan/scanners/quickintel"
+	"github.com/s-Amine/token-scan/transport"
+)
+
+// fixtureHosts are the hosts each provider is recorded from.
+var fixtureHosts = map[string]string{
+	SourceGoPlus:     "api.gopluslabs.io",
+	SourceHoneypot:   "api.honeypot.is",
+	SourceQuickIntel: "app.quickintel.io",
+}
+
+// premiumFixtureDir holds a QuickIntel response of the premium tier, with
+// the tokenDynamicDetails fields the basic tier leaves null.
+const premiumFixtureDir = "testdata/premium"
+
+// recordedResponse returns the response of provider for address recorded in
+// dir as a JSON tree. For GoPlus, the tree is the token security result of
+// address.
+func recordedResponse(t *testing.T, dir, provider, address string) map[string]interface{} {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fixture transport.Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		request := fixture.Request
+		if !strings.Contains(request.URL, "://"+fixtureHosts[provider]+"/") || !strings.Contains(request.URL+request.Body, address) {
+			continue
+		}
+
+		var body map[string]interface{}
+		decoder := json.NewDecoder(strings.NewReader(fixture.Response.Body))
+		decoder.UseNumber()
+		if err := decoder.Decode(&body); err != nil {
+			t.Fatalf("%s: decoding response: %v", path, err)
+		}
+		if provider == SourceGoPlus {
+			result, _ := body["result"].(map[string]interface{})
+			body, _ = result[address].(map[string]interface{})
+		}
+		return body
+	}
+	t.Fatalf("no %s response recorded for %s", provider, address)
+	return nil
+}
+
+// lookupPath returns the value at the dotted path of tree.
+func lookupPath(tree map[string]interface{}, path string) (interface{}, bool) {
+	keys := strings.Split(path, ".")
+	node := tree
+	for _, key := range keys[:len(keys)-1] {
+		var ok bool
+		if node, ok = node[key].(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	value, ok := node[keys[len(keys)-1]]
+	return value, ok
+}
+
+// withPath returns a deep copy of tree with the value at the dotted path replaced.
+func withPath(t *testing.T, tree map[string]interface{}, path string, value interface{}) map[string]interface{} {
+	t.Helper()
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var copied map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&copied); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := strings.Split(path, ".")
+	node := copied
+	for _, key := range keys[:len(keys)-1] {
+		node = node[key].(map[string]interface{})
+	}
+	node[keys[len(keys)-1]] = value
+	return copied
+}
+
+// mapResponse decodes tree as a response of provider and maps it.
+func mapResponse(t *testing.T, provider string, tree map[string]interface{}) *TokenInfo {
+	t.Helper()
+
+	data, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	switch provider {
+	case SourceGoPlus:
+		var r models.ResponseWrapperTokenSecurityResultAnon
+		if err := json.Unmarshal(data, &r); err != nil {
+			t.Fatalf("decoding GoPlus result: %v", err)
+		}
+		return InitTokenInfoFromGoPlus(r)
+	case SourceHoneypot:
+		var r ishoneypot.HoneypotResponse
+		if _, err := decode.Decode(data, &r); err != nil {
+			t.Fatalf("decoding honeypot.is response: %v", err)
+		}
+		return InitTokenInfoFromHoneypotResponse(r, scanTime)
+	case SourceQuickIntel:
+		var r quickintel.QuickIntelResponse
+		if _, err := decode.Decode(data, &r); err != nil {
+			t.Fatalf("decoding QuickIntel response: %v", err)
+		}
+		return InitTokenInfoFromQuickIntelResponse(r)
+	}
+	t.Fatalf("unknown provider %s", provider)
+	return nil
+}
+
+// unifiedField returns the value of the TokenInfo field with the JSON name.
+func unifiedField(info *TokenInfo, name string) (interface{}, bool) {
+	v := reflect.ValueOf(info).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		tag := v.Type().Field(i).Tag.Get("json")
+		if strings.Split(tag, ",")[0] == name {
+			return v.Field(i).Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// expectedValue converts a provider value to the type of the unified field
+// like, as mapping m does: GoPlus flags are "0"/"1" strings, percentages
+// become fractions and a missing value is the zero value.
+func expectedValue(t *testing.T, m FieldMapping, source interface{}, like interface{}) interface{} {
+	t.Helper()
+
+	if v, ok := source.(json.Number); ok && m.Percent {
+		f, err := v.Float64()
+		if err != nil {
+			t.Fatalf("source value %v: %v", v, err)
+		}
+		return strconv.FormatFloat(f/100, 'f', -1, 64)
+	}
+	switch like.(type) {
+	case bool:
+		switch v := source.(type) {
+		case bool:
+			return v
+		case string:
+			return v == "1"
+		case nil:
+			return false
+		}
+	case string:
+		switch v := source.(type) {
+		case string:
+			return v
+		case nil:
+			return ""
+		}
+	case int:
+		switch v := source.(type) {
+		case json.Number:
+			n, err := v.Int64()
+			if err != nil {
+				t.Fatalf("source value %v: %v", v, err)
+			}
+			return int(n)
+		case nil:
+			return 0
+		}
+	}
+	t.Fatalf("cannot convert source value %#v to %T", source, like)
+	return nil
+}
+
+// mutatedValue returns a provider value different from source, of the same kind.
+func mutatedValue(source interface{}, like interface{}) interface{} {
+	switch v := source.(type) {
+	case bool:
+		return !v
+	case json.Number:
+		return json.Number(v.String() + "7")
+	case string:
+		switch {
+		case v == "1":
+			return "0"
+		case v == "0":
+			return "1"
+		}
+		// Amounts are numeric strings
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			return v + "7"
+		}
+		return v + "-mutated"
+	}
+	switch like.(type) {
+	case bool:
+		return true
+	case int:
+		return json.Number("7")
+	}
+	return "mutated"
+}
+
+// providerMappings returns the described mappings of provider.
+func providerMappings(provider string) []FieldMapping {
+	var mappings []FieldMapping
+	for _, m := range FieldMappings() {
+		if m.Provider == provider {
+			mappings = append(mappings, m)
+		}
+	}
+	return mappings
+}
+
+// checkGolden checks that every mapping of provider copies its source field
+// of the recorded response into the unified field.
+func checkGolden(t *testing.T, address, provider string, response map[string]interface{}) {
+	t.Helper()
+
+	info := mapResponse(t, provider, response)
+	for _, m := range providerMappings(provider) {
+		source, ok := lookupPath(response, m.SourceField)
+		if !ok {
+			t.Errorf("%s %s: source field %s is not in the recorded response", address, provider, m.SourceField)
+			continue
+		}
+		got, ok := unifiedField(info, m.Field)
+		if !ok {
+			t.Errorf("%s: unified field %s does not exist", provider, m.Field)
+			continue
+		}
+		if want := expectedValue(t, m, source, got); got != want {
+			t.Errorf("%s %s: got %s = %v, want %v from %s", address, provider, m.Field, got, want, m.SourceField)
+		}
+	}
+}
+
+// checkSources checks that changing the source field of each mapping of
+// provider changes its unified field and no other. Sources the response
+// leaves null, such as the premium fields on the basic tier, are skipped.
+func checkSources(t *testing.T, provider string, response map[string]interface{}) {
+	t.Helper()
+
+	original := mapResponse(t, provider, response)
+	for _, m := range providerMappings(provider) {
+		source, _ := lookupPath(response, m.SourceField)
+		if source == nil {
+			continue
+		}
+		before, _ := unifiedField(original, m.Field)
+		mutated := mutatedValue(source, before)
+		info := mapResponse(t, provider, withPath(t, response, m.SourceField, mutated))
+
+		// The mapped field follows its source field, and no other field moves
+		after, _ := unifiedField(info, m.Field)
+		if want := expectedValue(t, m, mutated, before); after != want || after == before {
+			t.Errorf("%s: changing %s to %v: got %s = %v, want %v", provider, m.SourceField, mutated, m.Field, after, want)
+		}
+		for field := range FieldSemantics {
+			if field == m.Field {
+				continue
+			}
+			before, _ := unifiedField(original, field)
+			after, _ := unifiedField(info, field)
+			if before != after {
+				t.Errorf("%s: changing %s changed %s from %v to %v", provider, m.SourceField, field, before, after)
+			}
+		}
+	}
+}
+
+func TestFieldMappingsGolden(t *testing.T) {
+	for _, address := range []string{cleanToken, honeypotToken} {
+		for _, provider := range []string{SourceGoPlus, SourceHoneypot, SourceQuickIntel} {
+			checkGolden(t, address, provider, recordedResponse(t, fixtureDir, provider, address))
+		}
+	}
+}
+
+func TestFieldMappingsSource(t *testing.T) {
+	for _, provider := range []string{SourceGoPlus, SourceHoneypot, SourceQuickIntel} {
+		checkSources(t, provider, recordedResponse(t, fixtureDir, provider, cleanToken))
+	}
+}
+
+func TestPremiumFieldMappings(t *testing.T) {
+	response := recordedResponse(t, premiumFixtureDir, SourceQuickIntel, cleanToken)
+	for _, m := range quickIntelPremiumMappings {
+		if source, _ := lookupPath(response, m.source); source == nil {
+			t.Fatalf("premium fixture leaves %s null", m.source)
+		}
+	}
+	checkGolden(t, cleanToken, SourceQuickIntel, response)
+	checkSources(t, SourceQuickIntel, response)
+
+	// Premium data reaches the verdict
+	info := mapResponse(t, SourceQuickIntel, response)
+	verdict := AssessRisk(info)
+	for _, code := range []string{"high_sell_tax", "high_post_reenable_sell_tax"} {
+		if !hasFactor(verdict, code) {
+			t.Errorf("premium QuickIntel scan: no %s factor in %+v", code, verdict.Factors)
+		}
+	}
+
+	// The basic tier returns the premium fields as null: none is reported
+	basic := mapResponse(t, SourceQuickIntel, recordedResponse(t, fixtureDir, SourceQuickIntel, cleanToken))
+	for _, m := range quickIntelPremiumMappings {
+		if basic.isReported(m.field) {
+			t.Errorf("basic QuickIntel scan reports premium field %s", m.field)
+		}
+	}
+}
+
+func TestFieldSemantics(t *testing.T) {
+	mapped := make(map[string]bool)
+	for _, m := range FieldMappings() {
+		mapped[m.Field] = true
+		if _, ok := FieldSemantics[m.Field]; !ok {
+			t.Errorf("mapped field %s has no semantics", m.Field)
+		}
+	}
+	for field := range FieldSemantics {
+		if !mapped[field] {
+			t.Errorf("documented field %s is not mapped from any provider", field)
+		}
+	}
+}
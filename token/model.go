@@ -4,14 +4,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/models"
 	"github.com/s-Amine/token-scan/scanners/ishoneypot"
 	"github.com/s-Amine/token-scan/scanners/quickintel"
 )
 
+// Provider names reported in TokenInfo.Source and in disagreements.
+const (
+	SourceGoPlus     = "goplus"
+	SourceHoneypot   = "honeypot"
+	SourceQuickIntel = "quickintel"
+)
+
 // TokenInfo represents information about a token.
 type TokenInfo struct {
+	// Source is the provider the information comes from; it is empty once unified.
+	Source                     string `json:"source,omitempty"`
 	TokenName                  string `json:"token_name,omitempty"`
 	TokenSymbol                string `json:"token_symbol,omitempty"`
 	Decimals                   int    `json:"decimals,omitempty"`
@@ -19,6 +29,7 @@ type TokenInfo struct {
 	IsHoneypot                 bool   `json:"is_honeypot,omitempty"`
 	IsOpenSource               bool   `json:"is_open_source,omitempty"`
 	IsWhitelisted              bool   `json:"is_whitelisted,omitempty"`
+	CanWhitelist               bool   `json:"can_whitelist,omitempty"`
 	CanTakeBackOwnership       bool   `json:"can_take_back_ownership,omitempty"`
 	OwnerChangeBalance         bool   `json:"owner_change_balance,omitempty"`
 	CannotBuy                  bool   `json:"cannot_buy,omitempty"`
@@ -32,6 +43,45 @@ type TokenInfo struct {
 	ExternalCall               bool   `json:"external_call,omitempty"`
 	TradingCooldown            bool   `json:"trading_cooldown,omitempty"`
 	PersonalSlippageModifiable bool   `json:"personal_slippage_modifiable,omitempty"`
+
+	// The fields below are only returned by the QuickIntel premium tier.
+	// Percentages are converted to fractions, like the taxes above.
+	HoneypotReason      string `json:"honeypot_reason,omitempty"`
+	TransferTax         string `json:"transfer_tax,omitempty"`
+	PostReenableBuyTax  string `json:"post_reenable_buy_tax,omitempty"`
+	PostReenableSellTax string `json:"post_reenable_sell_tax,omitempty"`
+	MaxTransaction      string `json:"max_transaction,omitempty"`
+	MaxTransactionShare string `json:"max_transaction_share,omitempty"`
+	MaxWallet           string `json:"max_wallet,omitempty"`
+	MaxWalletShare      string `json:"max_wallet_share,omitempty"`
+	BurnedSupply        string `json:"burned_supply,omitempty"`
+	LPSupply            string `json:"lp_supply,omitempty"`
+	LPBurnedShare       string `json:"lp_burned_share,omitempty"`
+	LPLockedShare       string `json:"lp_locked_share,omitempty"`
+	LPLockedUntil       int    `json:"lp_locked_until,omitempty"`
+	LPHolderCount       int    `json:"lp_holder_count,omitempty"`
+	HolderCount         int    `json:"holder_count,omitempty"`
+	PriceImpact         string `json:"price_impact,omitempty"`
+
+	// Sources are the providers that reported data about the token. A
+	// provider whose scan failed or returned nothing is left out.
+	Sources []string `json:"sources,omitempty"`
+
+	HolderAnalysis *HolderAnalysis `json:"holder_analysis,omitempty"`
+	Liquidity      *Liquidity      `json:"liquidity,omitempty"`
+	Concentration  *Concentration  `json:"concentration,omitempty"`
+	Ownership      *Ownership      `json:"ownership,omitempty"`
+	Upgradeability *Upgradeability `json:"upgradeability,omitempty"`
+
+	TradingRestrictions *TradingRestrictions `json:"trading_restrictions,omitempty"`
+	ScamFindings        []Finding            `json:"scam_findings,omitempty"`
+	Risk                *RiskVerdict         `json:"risk,omitempty"`
+
+	// claims are the provider claims Ownership and Upgradeability were reconciled from.
+	claims []*claim
+	// reportedFields are the JSON names of the scalar fields a provider
+	// reported, as opposed to fields left at their zero value.
+	reportedFields map[string]bool
 }
 
 // ToJSON converts TokenInfo to JSON string.
@@ -43,6 +93,49 @@ func (t *TokenInfo) ToJSON() (string, error) {
 	return string(jsonData), nil
 }
 
+// setClaims records the given provider claims and reconciles them into
+// Ownership and Upgradeability.
+func (t *TokenInfo) setClaims(claims ...*claim) {
+	t.claims = claims
+	t.Ownership = reconcileOwnership(claims)
+	t.Upgradeability = reconcileUpgradeability(claims)
+	if t.Ownership != nil && t.Upgradeability != nil {
+		t.Ownership.IsProxy = t.Upgradeability.IsProxy
+		t.Ownership.Implementation = t.Upgradeability.Implementation
+	}
+}
+
+// addSource records that provider reported data about the token.
+func (t *TokenInfo) addSource(provider string) {
+	if t.hasSource(provider) {
+		return
+	}
+	t.Sources = append(t.Sources, provider)
+}
+
+// hasSource reports whether provider reported data about the token.
+func (t *TokenInfo) hasSource(provider string) bool {
+	for _, source := range t.Sources {
+		if source == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// setReported records that a provider reported the scalar field, by JSON name.
+func (t *TokenInfo) setReported(field string) {
+	if t.reportedFields == nil {
+		t.reportedFields = make(map[string]bool)
+	}
+	t.reportedFields[field] = true
+}
+
+// isReported reports whether a provider reported the scalar field, by JSON name.
+func (t *TokenInfo) isReported(field string) bool {
+	return t.reportedFields[field]
+}
+
 // setBoolField sets a boolean field based on the given value.
 func (t *TokenInfo) setBoolField(value string, field *bool) {
 	if value == "" {
@@ -60,59 +153,46 @@ func (t *TokenInfo) setBoolField(value string, field *bool) {
 
 // InitTokenInfoFromQuickIntelResponse initializes TokenInfo from QuickIntelResponse.
 func InitTokenInfoFromQuickIntelResponse(response quickintel.QuickIntelResponse) *TokenInfo {
-	tokenInfo := &TokenInfo{
-		TokenName:                  response.TokenDetails.TokenName,
-		TokenSymbol:                response.TokenDetails.TokenSymbol,
-		Decimals:                   response.TokenDetails.TokenDecimals,
-		IsHoneypot:                 response.QuickiAudit.HiddenOwner,
-		IsWhitelisted:              response.QuickiAudit.CanWhitelist,
-		IsMintable:                 response.QuickiAudit.CanMint,
-		TransferPausable:           response.QuickiAudit.CanPauseTrading,
-		IsBlacklisted:              response.QuickiAudit.CanBlacklist,
-		ExternalCall:               response.QuickiAudit.HasExternalContractRisk,
-		TradingCooldown:            response.QuickiAudit.HasTradingCooldown,
-		PersonalSlippageModifiable: false,
+	tokenInfo := &TokenInfo{Source: SourceQuickIntel}
+	applyMappings(quickIntelMappings, &response, tokenInfo)
+	if response.QuickiAudit.ContractAddress != "" || response.TokenDetails.TokenName != "" {
+		tokenInfo.addSource(SourceQuickIntel)
+		reportMappings(quickIntelMappings, tokenInfo)
 	}
+	applyMappings(quickIntelPremiumMappings, &response, tokenInfo)
+	tokenInfo.setClaims(claimFromQuickIntel(response))
+	tokenInfo.TradingRestrictions = mergeTradingRestrictions(tokenInfo.Ownership, newTradingRestrictionsFromQuickIntel(response))
+	tokenInfo.ScamFindings = newScamFindings(response)
 
 	return tokenInfo
 }
 
 // InitTokenInfoFromGoPlus initializes TokenInfo from GoPlus response.
 func InitTokenInfoFromGoPlus(r models.ResponseWrapperTokenSecurityResultAnon) *TokenInfo {
-	tokenInfo := &TokenInfo{
-		TokenName:   r.TokenName,
-		TokenSymbol: r.TokenSymbol,
-		BuyTax:      r.BuyTax,
-		SellTax:     r.SellTax,
-	}
-
-	tokenInfo.setBoolField(r.CanTakeBackOwnership, &tokenInfo.CanTakeBackOwnership)
-	tokenInfo.setBoolField(r.CannotBuy, &tokenInfo.CannotBuy)
-	tokenInfo.setBoolField(r.CannotSellAll, &tokenInfo.CannotSellAll)
-	tokenInfo.setBoolField(r.ExternalCall, &tokenInfo.ExternalCall)
-	tokenInfo.setBoolField(r.HiddenOwner, &tokenInfo.HiddenOwner)
-	tokenInfo.setBoolField(r.IsBlacklisted, &tokenInfo.IsBlacklisted)
-	tokenInfo.setBoolField(r.IsHoneypot, &tokenInfo.IsHoneypot)
-	tokenInfo.setBoolField(r.IsMintable, &tokenInfo.IsMintable)
-	tokenInfo.setBoolField(r.IsOpenSource, &tokenInfo.IsOpenSource)
-	tokenInfo.setBoolField(r.IsWhitelisted, &tokenInfo.IsWhitelisted)
-	tokenInfo.setBoolField(r.OwnerChangeBalance, &tokenInfo.OwnerChangeBalance)
-	tokenInfo.setBoolField(r.TradingCooldown, &tokenInfo.TradingCooldown)
-	tokenInfo.setBoolField(r.TransferPausable, &tokenInfo.TransferPausable)
+	tokenInfo := &TokenInfo{Source: SourceGoPlus}
+	applyMappings(goPlusMappings, &r, tokenInfo)
+	if len(tokenInfo.reportedFields) > 0 {
+		tokenInfo.addSource(SourceGoPlus)
+	}
+	tokenInfo.Concentration = newConcentration(r)
+	tokenInfo.setClaims(claimFromGoPlus(r))
+	tokenInfo.TradingRestrictions = mergeTradingRestrictions(tokenInfo.Ownership, newTradingRestrictionsFromGoPlus(r))
 
 	return tokenInfo
 }
 
 // InitTokenInfoFromHoneypotResponse initializes TokenInfo from Honeypot response.
-func InitTokenInfoFromHoneypotResponse(response ishoneypot.HoneypotResponse) *TokenInfo {
-	tokenInfo := &TokenInfo{
-		TokenName:     response.Token.Name,
-		TokenSymbol:   response.Token.Symbol,
-		Decimals:      response.Token.Decimals,
-		UniswapV2Pair: response.Pair.PairAddress,
-		IsHoneypot:    response.HoneypotResult.IsHoneypot,
-		IsOpenSource:  response.ContractCode.OpenSource,
+// The age of the pair is measured at scannedAt, the time of the scan.
+func InitTokenInfoFromHoneypotResponse(response ishoneypot.HoneypotResponse, scannedAt time.Time) *TokenInfo {
+	tokenInfo := &TokenInfo{Source: SourceHoneypot}
+	applyMappings(honeypotMappings, &response, tokenInfo)
+	if response.Token.Address != "" || response.Token.Name != "" {
+		tokenInfo.addSource(SourceHoneypot)
+		reportMappings(honeypotMappings, tokenInfo)
 	}
+	tokenInfo.HolderAnalysis = newHolderAnalysis(response)
+	tokenInfo.Liquidity = newLiquidity(response, scannedAt)
+	tokenInfo.setClaims(claimFromHoneypot(response))
 
 	return tokenInfo
 }
@@ -158,6 +238,7 @@ func UnifyTokenInfo(info1, info2, info3 *TokenInfo) *TokenInfo {
 	unifiedInfo.IsHoneypot = worstBool(info1.IsHoneypot, info2.IsHoneypot, info3.IsHoneypot)
 	unifiedInfo.IsOpenSource = worstBool(info1.IsOpenSource, info2.IsOpenSource, info3.IsOpenSource)
 	unifiedInfo.IsWhitelisted = worstBool(info1.IsWhitelisted, info2.IsWhitelisted, info3.IsWhitelisted)
+	unifiedInfo.CanWhitelist = worstBool(info1.CanWhitelist, info2.CanWhitelist, info3.CanWhitelist)
 	unifiedInfo.CanTakeBackOwnership = worstBool(info1.CanTakeBackOwnership, info2.CanTakeBackOwnership, info3.CanTakeBackOwnership)
 	unifiedInfo.OwnerChangeBalance = worstBool(info1.OwnerChangeBalance, info2.OwnerChangeBalance, info3.OwnerChangeBalance)
 	unifiedInfo.CannotBuy = worstBool(info1.CannotBuy, info2.CannotBuy, info3.CannotBuy)
@@ -170,7 +251,67 @@ func UnifyTokenInfo(info1, info2, info3 *TokenInfo) *TokenInfo {
 	unifiedInfo.SellTax = worstString(info1.SellTax, info2.SellTax, info3.SellTax)
 	unifiedInfo.ExternalCall = worstBool(info1.ExternalCall, info2.ExternalCall, info3.ExternalCall)
 	unifiedInfo.TradingCooldown = worstBool(info1.TradingCooldown, info2.TradingCooldown, info3.TradingCooldown)
-	unifiedInfo.PersonalSlippageModifiable = false
+	unifiedInfo.PersonalSlippageModifiable = worstBool(info1.PersonalSlippageModifiable, info2.PersonalSlippageModifiable, info3.PersonalSlippageModifiable)
+	unifiedInfo.HoneypotReason = worstString(info1.HoneypotReason, info2.HoneypotReason, info3.HoneypotReason)
+	unifiedInfo.TransferTax = worstString(info1.TransferTax, info2.TransferTax, info3.TransferTax)
+	unifiedInfo.PostReenableBuyTax = worstString(info1.PostReenableBuyTax, info2.PostReenableBuyTax, info3.PostReenableBuyTax)
+	unifiedInfo.PostReenableSellTax = worstString(info1.PostReenableSellTax, info2.PostReenableSellTax, info3.PostReenableSellTax)
+	unifiedInfo.MaxTransaction = worstString(info1.MaxTransaction, info2.MaxTransaction, info3.MaxTransaction)
+	unifiedInfo.MaxTransactionShare = worstString(info1.MaxTransactionShare, info2.MaxTransactionShare, info3.MaxTransactionShare)
+	unifiedInfo.MaxWallet = worstString(info1.MaxWallet, info2.MaxWallet, info3.MaxWallet)
+	unifiedInfo.MaxWalletShare = worstString(info1.MaxWalletShare, info2.MaxWalletShare, info3.MaxWalletShare)
+	unifiedInfo.BurnedSupply = worstString(info1.BurnedSupply, info2.BurnedSupply, info3.BurnedSupply)
+	unifiedInfo.LPSupply = worstString(info1.LPSupply, info2.LPSupply, info3.LPSupply)
+	unifiedInfo.LPBurnedShare = worstString(info1.LPBurnedShare, info2.LPBurnedShare, info3.LPBurnedShare)
+	unifiedInfo.LPLockedShare = worstString(info1.LPLockedShare, info2.LPLockedShare, info3.LPLockedShare)
+	unifiedInfo.LPLockedUntil = worstInt(info1.LPLockedUntil, info2.LPLockedUntil, info3.LPLockedUntil)
+	unifiedInfo.LPHolderCount = worstInt(info1.LPHolderCount, info2.LPHolderCount, info3.LPHolderCount)
+	unifiedInfo.HolderCount = worstInt(info1.HolderCount, info2.HolderCount, info3.HolderCount)
+	unifiedInfo.PriceImpact = worstString(info1.PriceImpact, info2.PriceImpact, info3.PriceImpact)
+	unifiedInfo.HolderAnalysis = firstHolderAnalysis(info1.HolderAnalysis, info2.HolderAnalysis, info3.HolderAnalysis)
+	unifiedInfo.Liquidity = firstLiquidity(info1.Liquidity, info2.Liquidity, info3.Liquidity)
+	unifiedInfo.Concentration = firstConcentration(info1.Concentration, info2.Concentration, info3.Concentration)
+	unifiedInfo.setClaims(collectClaims(info1, info2, info3)...)
+	unifiedInfo.TradingRestrictions = mergeTradingRestrictions(unifiedInfo.Ownership, info1.TradingRestrictions, info2.TradingRestrictions, info3.TradingRestrictions)
+	for _, info := range []*TokenInfo{info1, info2, info3} {
+		unifiedInfo.ScamFindings = append(unifiedInfo.ScamFindings, info.ScamFindings...)
+		for _, source := range info.Sources {
+			unifiedInfo.addSource(source)
+		}
+		for field := range info.reportedFields {
+			unifiedInfo.setReported(field)
+		}
+	}
 
 	return unifiedInfo
 }
+
+// firstHolderAnalysis returns the first non-nil holder analysis.
+func firstHolderAnalysis(analyses ...*HolderAnalysis) *HolderAnalysis {
+	for _, analysis := range analyses {
+		if analysis != nil {
+			return analysis
+		}
+	}
+	return nil
+}
+
+// firstLiquidity returns the first non-nil liquidity section.
+func firstLiquidity(sections ...*Liquidity) *Liquidity {
+	for _, section := range sections {
+		if section != nil {
+			return section
+		}
+	}
+	return nil
+}
+
+// firstConcentration returns the first non-nil concentration section.
+func firstConcentration(sections ...*Concentration) *Concentration {
+	for _, section := range sections {
+		if section != nil {
+			return section
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package token
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Ownership represents who controls the contract, reconciled across providers.
+// Worst case wins: the contract counts as renounced only when no provider
+// reports a live owner. IsProxy and Implementation repeat the proxy status
+// and implementation address reconciled in Upgradeability.
+type Ownership struct {
+	Creator            string         `json:"creator,omitempty"`
+	Owner              string         `json:"owner,omitempty"`
+	Renounced          bool           `json:"renounced"`
+	HiddenOwner        bool           `json:"hidden_owner"`
+	OnlyOwnerFunctions []string       `json:"only_owner_functions,omitempty"`
+	IsProxy            bool           `json:"is_proxy"`
+	Implementation     string         `json:"implementation,omitempty"`
+	Disagreements      []Disagreement `json:"disagreements,omitempty"`
+}
+
+// reconcileOwnership merges provider claims into one ownership section,
+// recording every field the providers disagree on. It returns nil when no
+// provider reports ownership.
+func reconcileOwnership(claims []*claim) *Ownership {
+	o := &Ownership{Renounced: true}
+	creators := make(map[string]string)
+	owners := make(map[string]string)
+	renounced := make(map[string]string)
+	hidden := make(map[string]string)
+	functions := make(map[string]bool)
+
+	for _, claim := range claims {
+		if claim == nil {
+			continue
+		}
+		if claim.creator != "" {
+			creators[claim.source] = strings.ToLower(claim.creator)
+			if o.Creator == "" {
+				o.Creator = claim.creator
+			}
+		}
+		if claim.owner != nil {
+			owners[claim.source] = strings.ToLower(*claim.owner)
+			if o.Owner == "" || (isRenouncedOwner(o.Owner) && !isRenouncedOwner(*claim.owner)) {
+				o.Owner = *claim.owner
+			}
+		}
+		if claim.renounced != nil {
+			renounced[claim.source] = strconv.FormatBool(*claim.renounced)
+			o.Renounced = o.Renounced && *claim.renounced
+		}
+		if claim.hiddenOwner != nil {
+			hidden[claim.source] = strconv.FormatBool(*claim.hiddenOwner)
+			o.HiddenOwner = o.HiddenOwner || *claim.hiddenOwner
+		}
+		for _, function := range claim.onlyOwnerFunctions {
+			if !functions[function] {
+				functions[function] = true
+				o.OnlyOwnerFunctions = append(o.OnlyOwnerFunctions, function)
+			}
+		}
+	}
+	if len(creators) == 0 && len(owners) == 0 && len(renounced) == 0 && len(hidden) == 0 {
+		return nil
+	}
+	if len(renounced) == 0 {
+		o.Renounced = false
+	}
+	// A hidden owner keeps control whatever the visible owner is.
+	if o.HiddenOwner {
+		o.Renounced = false
+	}
+
+	o.Disagreements = appendDisagreement(o.Disagreements, "creator", creators)
+	o.Disagreements = appendDisagreement(o.Disagreements, "owner", owners)
+	o.Disagreements = appendDisagreement(o.Disagreements, "renounced", renounced)
+	o.Disagreements = appendDisagreement(o.Disagreements, "hidden_owner", hidden)
+
+	return o
+}
@@ -0,0 +1,279 @@
+package token
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/models"
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/scanners/goplus"
+	"github.com/s-Amine/token-scan/scanners/ishoneypot"
+	"github.com/s-Amine/token-scan/scanners/quickintel"
+	"github.com/s-Amine/token-scan/transport"
+)
+
+// fixtureDir holds the provider responses, recorded with transport.Recorder,
+// for a clean token and a honeypot on Ethereum.
+const fixtureDir = "testdata/fixtures"
+
+// Tokens with recorded responses.
+const (
+	cleanToken    = "0x000000000000000000000000000000000000c1ea"
+	honeypotToken = "0x0000000000000000000000000000000000000bad"
+)
+
+// scanTime is the time the recorded scans are assessed at, 12 hours after the
+// honeypot pair was created.
+var scanTime = time.Date(2025, time.October, 19, 3, 6, 40, 0, time.UTC)
+
+// responses are the responses of every provider for one token.
+type responses struct {
+	goPlus     models.ResponseWrapperTokenSecurityResultAnon
+	honeypot   ishoneypot.HoneypotResponse
+	quickIntel quickintel.QuickIntelResponse
+}
+
+// replayScan scans address with every provider, serving the recorded
+// fixtures instead of the network.
+func replayScan(t *testing.T, address string) responses {
+	t.Helper()
+
+	replayer, err := transport.NewReplayer(fixtureDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport.SetRoundTripper(replayer)
+	t.Cleanup(func() { transport.SetRoundTripper(nil) })
+
+	var r responses
+	if r.goPlus, err = goplus.ScanChain(chain.Default, address); err != nil {
+		t.Fatalf("goplus scan: %v", err)
+	}
+	if r.honeypot, err = ishoneypot.ScanChain(chain.Default, address); err != nil {
+		t.Fatalf("honeypot scan: %v", err)
+	}
+	if r.quickIntel, err = quickintel.ScanChain(chain.Default, address); err != nil {
+		t.Fatalf("quickintel scan: %v", err)
+	}
+	return r
+}
+
+// hasFactor reports whether verdict has a risk factor with code.
+func hasFactor(verdict *RiskVerdict, code string) bool {
+	for _, factor := range verdict.Factors {
+		if factor.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func TestInitTokenInfoFromGoPlus(t *testing.T) {
+	clean := InitTokenInfoFromGoPlus(replayScan(t, cleanToken).goPlus)
+	if clean.Source != SourceGoPlus || clean.TokenName != "Clear Water" || clean.TokenSymbol != "CLWT" {
+		t.Errorf("clean token: got source %q, name %q, symbol %q", clean.Source, clean.TokenName, clean.TokenSymbol)
+	}
+	if clean.BuyTax != "0.01" || clean.SellTax != "0.02" {
+		t.Errorf("clean token: got taxes %q/%q, want 0.01/0.02", clean.BuyTax, clean.SellTax)
+	}
+	if !clean.IsOpenSource || clean.IsHoneypot || clean.HiddenOwner {
+		t.Errorf("clean token: got open source %v, honeypot %v, hidden owner %v", clean.IsOpenSource, clean.IsHoneypot, clean.HiddenOwner)
+	}
+	if c := clean.Concentration; c == nil || c.HolderCount != 48211 || c.LPBurnedShare != 0.62 || c.LPLockedShare != 0.35 {
+		t.Errorf("clean token: got concentration %+v", c)
+	}
+	if o := clean.Ownership; o == nil || !o.Renounced {
+		t.Errorf("clean token: got ownership %+v, want renounced", o)
+	}
+
+	rug := InitTokenInfoFromGoPlus(replayScan(t, honeypotToken).goPlus)
+	if !rug.IsHoneypot || !rug.CannotSellAll || !rug.HiddenOwner || !rug.OwnerChangeBalance || rug.IsOpenSource {
+		t.Errorf("honeypot: got %+v", rug)
+	}
+	if o := rug.Ownership; o == nil || o.Renounced || o.Owner != "0xab8483f64d9c6d1ecf9b849ae677dd3315835cb2" {
+		t.Errorf("honeypot: got ownership %+v", o)
+	}
+}
+
+func TestInitTokenInfoFromHoneypotResponse(t *testing.T) {
+	clean := InitTokenInfoFromHoneypotResponse(replayScan(t, cleanToken).honeypot, scanTime)
+	if clean.Source != SourceHoneypot || clean.Decimals != 18 || clean.UniswapV2Pair != "0x000000000000000000000000000000000000c1e2" {
+		t.Errorf("clean token: got source %q, decimals %d, pair %q", clean.Source, clean.Decimals, clean.UniswapV2Pair)
+	}
+	if clean.IsHoneypot || !clean.IsOpenSource {
+		t.Errorf("clean token: got honeypot %v, open source %v", clean.IsHoneypot, clean.IsOpenSource)
+	}
+	if h := clean.HolderAnalysis; h == nil || h.Holders != 1000 || h.Failed != 2 || h.SiphonedDetected {
+		t.Errorf("clean token: got holder analysis %+v", h)
+	}
+	if l := clean.Liquidity; l == nil || l.QuoteSymbol != "WETH" || l.TokenReserve.String() != "84210000000000000000000000" || l.QuoteReserve.String() != "612000000000000000000" {
+		t.Errorf("clean token: got liquidity %+v", l)
+	}
+
+	rug := InitTokenInfoFromHoneypotResponse(replayScan(t, honeypotToken).honeypot, scanTime)
+	if !rug.IsHoneypot || rug.IsOpenSource {
+		t.Errorf("honeypot: got honeypot %v, open source %v", rug.IsHoneypot, rug.IsOpenSource)
+	}
+	if h := rug.HolderAnalysis; h == nil || h.Failed != 205 || h.Siphoned != 3 || !h.SiphonedDetected {
+		t.Errorf("honeypot: got holder analysis %+v", h)
+	}
+	if l := rug.Liquidity; l == nil || l.AgeHours != 12 {
+		t.Errorf("honeypot: got liquidity %+v, want a pair created 12 hours before the scan", l)
+	}
+	if u := rug.Upgradeability; u == nil || !u.IsProxy || !u.HasProxyCalls {
+		t.Errorf("honeypot: got upgradeability %+v", u)
+	}
+}
+
+func TestInitTokenInfoFromQuickIntelResponse(t *testing.T) {
+	clean := InitTokenInfoFromQuickIntelResponse(replayScan(t, cleanToken).quickIntel)
+	if clean.Source != SourceQuickIntel || clean.TokenName != "Clear Water" || clean.Decimals != 18 {
+		t.Errorf("clean token: got source %q, name %q, decimals %d", clean.Source, clean.TokenName, clean.Decimals)
+	}
+	if clean.IsHoneypot || clean.HiddenOwner || len(clean.ScamFindings) != 0 {
+		t.Errorf("clean token: got honeypot %v, hidden owner %v, findings %+v", clean.IsHoneypot, clean.HiddenOwner, clean.ScamFindings)
+	}
+
+	rug := InitTokenInfoFromQuickIntelResponse(replayScan(t, honeypotToken).quickIntel)
+	if !rug.IsHoneypot || !rug.HiddenOwner || !rug.IsMintable || !rug.TransferPausable {
+		t.Errorf("honeypot: got %+v", rug)
+	}
+	if u := rug.Upgradeability; u == nil || u.Implementation != "0x8ba1f109551bd432803012645ac136ddd64dba72" {
+		t.Errorf("honeypot: got upgradeability %+v", u)
+	}
+	kinds := make(map[string]bool)
+	for _, finding := range rug.ScamFindings {
+		kinds[finding.Kind] = true
+	}
+	for _, kind := range []string{FindingMatchedScam, FindingScamFunctions, FindingScamWalletFunding, FindingObfuscatedAddress, FindingSuspiciousFunctions, FindingGeneralVulnerability} {
+		if !kinds[kind] {
+			t.Errorf("honeypot: missing %s finding in %+v", kind, rug.ScamFindings)
+		}
+	}
+}
+
+func TestUnifyTokenInfo(t *testing.T) {
+	tests := []struct {
+		address   string
+		level     string
+		renounced bool
+		factors   []string
+	}{
+		{address: cleanToken, level: VerdictSafe, renounced: true},
+		{
+			address: honeypotToken,
+			level:   VerdictDanger,
+			factors: []string{"honeypot", "holders_unable_to_sell", "siphoned_wallets", "thin_liquidity", "upgradeable_with_owner", FindingMatchedScam},
+		},
+	}
+	for _, tt := range tests {
+		r := replayScan(t, tt.address)
+		unified := UnifyTokenInfo(InitTokenInfoFromGoPlus(r.goPlus), InitTokenInfoFromHoneypotResponse(r.honeypot, scanTime), InitTokenInfoFromQuickIntelResponse(r.quickIntel))
+		unified.Risk = AssessRisk(unified)
+
+		if unified.Source != "" {
+			t.Errorf("%s: got source %q, want none once unified", tt.address, unified.Source)
+		}
+		if unified.Risk.Level != tt.level {
+			t.Errorf("%s: got verdict %s (%+v), want %s", tt.address, unified.Risk.Level, unified.Risk.Factors, tt.level)
+		}
+		if unified.IsHoneypot != (tt.level == VerdictDanger) {
+			t.Errorf("%s: got honeypot %v", tt.address, unified.IsHoneypot)
+		}
+		if unified.Ownership == nil || unified.Ownership.Renounced != tt.renounced {
+			t.Errorf("%s: got ownership %+v, want renounced %v", tt.address, unified.Ownership, tt.renounced)
+		}
+		if o, u := unified.Ownership, unified.Upgradeability; o != nil && u != nil && (o.IsProxy != u.IsProxy || o.Implementation != u.Implementation) {
+			t.Errorf("%s: got ownership proxy %v (%q), want upgradeability proxy %v (%q)", tt.address, o.IsProxy, o.Implementation, u.IsProxy, u.Implementation)
+		}
+		if unified.HolderAnalysis == nil || unified.Liquidity == nil || unified.Concentration == nil {
+			t.Errorf("%s: got holder analysis %v, liquidity %v, concentration %v", tt.address, unified.HolderAnalysis, unified.Liquidity, unified.Concentration)
+		}
+		for _, code := range tt.factors {
+			if !hasFactor(unified.Risk, code) {
+				t.Errorf("%s: missing risk factor %s in %+v", tt.address, code, unified.Risk.Factors)
+			}
+		}
+	}
+}
+
+func TestAssessRiskMissingProviders(t *testing.T) {
+	clean := replayScan(t, cleanToken)
+	rug := replayScan(t, honeypotToken)
+	tests := []struct {
+		name    string
+		info    *TokenInfo
+		level   string
+		missing []string
+	}{
+		{
+			name:    "no provider",
+			info:    UnifyTokenInfo(InitTokenInfoFromGoPlus(responses{}.goPlus), InitTokenInfoFromHoneypotResponse(responses{}.honeypot, scanTime), InitTokenInfoFromQuickIntelResponse(responses{}.quickIntel)),
+			level:   VerdictUnknown,
+			missing: []string{SourceGoPlus, SourceHoneypot, SourceQuickIntel},
+		},
+		{
+			name:    "clean token without QuickIntel",
+			info:    UnifyTokenInfo(InitTokenInfoFromGoPlus(clean.goPlus), InitTokenInfoFromHoneypotResponse(clean.honeypot, scanTime), InitTokenInfoFromQuickIntelResponse(responses{}.quickIntel)),
+			level:   VerdictUnknown,
+			missing: []string{SourceQuickIntel},
+		},
+		{
+			name:    "honeypot without GoPlus",
+			info:    UnifyTokenInfo(InitTokenInfoFromGoPlus(responses{}.goPlus), InitTokenInfoFromHoneypotResponse(rug.honeypot, scanTime), InitTokenInfoFromQuickIntelResponse(rug.quickIntel)),
+			level:   VerdictDanger,
+			missing: []string{SourceGoPlus},
+		},
+	}
+	for _, tt := range tests {
+		verdict := AssessRisk(tt.info)
+		if verdict.Level != tt.level {
+			t.Errorf("%s: got verdict %s (%+v), want %s", tt.name, verdict.Level, verdict.Factors, tt.level)
+		}
+		if !verdict.Incomplete || fmt.Sprint(verdict.MissingSources) != fmt.Sprint(tt.missing) {
+			t.Errorf("%s: got incomplete %v, missing sources %v, want %v", tt.name, verdict.Incomplete, verdict.MissingSources, tt.missing)
+		}
+	}
+
+	// Only QuickIntel answered: nobody reported whether the source is verified
+	quickIntelOnly := UnifyTokenInfo(InitTokenInfoFromGoPlus(responses{}.goPlus), InitTokenInfoFromHoneypotResponse(responses{}.honeypot, scanTime), InitTokenInfoFromQuickIntelResponse(clean.quickIntel))
+	if verdict := AssessRisk(quickIntelOnly); hasFactor(verdict, "closed_source") {
+		t.Errorf("got closed_source factor without any provider reporting it: %+v", verdict.Factors)
+	}
+}
+
+func TestTradingRestrictionsFromGoPlusRenouncement(t *testing.T) {
+	r := replayScan(t, honeypotToken).goPlus
+	tests := []struct {
+		name                 string
+		hiddenOwner          string
+		canTakeBackOwnership string
+		afterRenounce        bool
+	}{
+		{name: "hidden owner and reclaimable ownership", hiddenOwner: "1", canTakeBackOwnership: "1", afterRenounce: true},
+		{name: "hidden owner", hiddenOwner: "1", canTakeBackOwnership: "0", afterRenounce: true},
+		{name: "reclaimable ownership", hiddenOwner: "0", canTakeBackOwnership: "1", afterRenounce: true},
+		{name: "plain owner", hiddenOwner: "0", canTakeBackOwnership: "0", afterRenounce: false},
+	}
+	for _, tt := range tests {
+		r.HiddenOwner, r.CanTakeBackOwnership = tt.hiddenOwner, tt.canTakeBackOwnership
+		restrictions := newTradingRestrictionsFromGoPlus(r)
+		for _, entry := range []namedRestriction{{"update_fees", &restrictions.UpdateFees}, {"blacklist", &restrictions.Blacklist}, {"pause_trading", &restrictions.PauseTrading}} {
+			if !entry.restriction.Capable || entry.restriction.ExercisableAfterRenounce != tt.afterRenounce {
+				t.Errorf("%s: got %s %+v, want exercisable after renounce %v", tt.name, entry.name, *entry.restriction, tt.afterRenounce)
+			}
+		}
+	}
+}
+
+func TestScamFunctionsWithoutMatchedScam(t *testing.T) {
+	response := replayScan(t, cleanToken).quickIntel
+	response.QuickiAudit.HasScams = false
+	response.QuickiAudit.ScamFuncs = "setBots"
+
+	findings := newScamFindings(response)
+	if len(findings) != 1 || findings[0].Kind != FindingScamFunctions || len(findings[0].Items) != 1 || findings[0].Items[0] != "setBots" {
+		t.Errorf("got findings %+v, want only the scam functions", findings)
+	}
+}
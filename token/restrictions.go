@@ -0,0 +1,176 @@
+package token
+
+import (
+	"fmt"
+
+	"github.com/GoPlusSecurity/goplus-sdk-go/pkg/gen/models"
+	"github.com/s-Amine/token-scan/scanners/quickintel"
+)
+
+// Restriction represents a trading restriction the contract owner can impose.
+type Restriction struct {
+	// Capable reports whether the contract has the capability at all.
+	Capable bool `json:"capable"`
+	// ExercisableAfterRenounce reports whether the capability survives
+	// renouncing ownership.
+	ExercisableAfterRenounce bool `json:"exercisable_after_renounce"`
+	// Exercisable reports whether the capability can be used today, given
+	// the reconciled ownership status.
+	Exercisable bool `json:"exercisable"`
+}
+
+// TradingRestrictions represents the trading restriction capabilities of the
+// contract, merged across QuickIntel and GoPlus. Worst case wins.
+type TradingRestrictions struct {
+	UpdateFees                Restriction `json:"update_fees"`
+	PersonalFees              Restriction `json:"personal_fees"`
+	UpdateMaxWallet           Restriction `json:"update_max_wallet"`
+	UpdateMaxTx               Restriction `json:"update_max_tx"`
+	UpdateAntiWhale           Restriction `json:"update_anti_whale"`
+	Blacklist                 Restriction `json:"blacklist"`
+	MultiBlacklist            Restriction `json:"multi_blacklist"`
+	Whitelist                 Restriction `json:"whitelist"`
+	PauseTrading              Restriction `json:"pause_trading"`
+	ModifiedTransfer          Restriction `json:"modified_transfer"`
+	AntiWhaleInForce          bool        `json:"anti_whale_in_force"`
+	TradingCooldown           bool        `json:"trading_cooldown"`
+	ModifiedTransferFunctions string      `json:"modified_transfer_functions,omitempty"`
+}
+
+// namedRestriction pairs a restriction with its JSON name.
+type namedRestriction struct {
+	name        string
+	restriction *Restriction
+}
+
+// restrictions lists every restriction with its name, for iteration.
+func (r *TradingRestrictions) restrictions() []namedRestriction {
+	return []namedRestriction{
+		{"update_fees", &r.UpdateFees},
+		{"personal_fees", &r.PersonalFees},
+		{"update_max_wallet", &r.UpdateMaxWallet},
+		{"update_max_tx", &r.UpdateMaxTx},
+		{"update_anti_whale", &r.UpdateAntiWhale},
+		{"blacklist", &r.Blacklist},
+		{"multi_blacklist", &r.MultiBlacklist},
+		{"whitelist", &r.Whitelist},
+		{"pause_trading", &r.PauseTrading},
+		{"modified_transfer", &r.ModifiedTransfer},
+	}
+}
+
+// newRestriction builds a restriction from a capability and the provider flag
+// telling whether renouncing ownership disables it.
+func newRestriction(capable, disabledByRenounce bool) Restriction {
+	return Restriction{
+		Capable:                  capable,
+		ExercisableAfterRenounce: capable && !disabledByRenounce,
+	}
+}
+
+// newTradingRestrictionsFromQuickIntel extracts the restrictions of a QuickIntel audit.
+// QuickIntel reports for most capabilities whether renouncing disables them;
+// capabilities without such a flag are assumed to survive renouncement.
+func newTradingRestrictionsFromQuickIntel(response quickintel.QuickIntelResponse) *TradingRestrictions {
+	audit := response.QuickiAudit
+	if audit.ContractAddress == "" && response.TokenDetails.TokenName == "" {
+		return nil
+	}
+	return &TradingRestrictions{
+		UpdateFees:                newRestriction(audit.CanUpdateFees, audit.CantUpdateFeesRenounced),
+		UpdateMaxWallet:           newRestriction(audit.CanUpdateMaxWallet, audit.CantUpdateMaxWalletRen),
+		UpdateMaxTx:               newRestriction(audit.CanUpdateMaxTx, audit.CantUpdateMaxTxRen),
+		Blacklist:                 newRestriction(audit.CanBlacklist, audit.CantBlacklistRenounced),
+		MultiBlacklist:            newRestriction(audit.CanMultiBlacklist, false),
+		Whitelist:                 newRestriction(audit.CanWhitelist, audit.CantWhitelistRenounced),
+		PauseTrading:              newRestriction(audit.CanPauseTrading, audit.CantPauseTradingRen),
+		ModifiedTransfer:          newRestriction(audit.HasModifiedTransferWarn, false),
+		TradingCooldown:           audit.HasTradingCooldown,
+		ModifiedTransferFunctions: audit.ModifiedTransferFuncs,
+	}
+}
+
+// newTradingRestrictionsFromGoPlus extracts the restrictions of a GoPlus result.
+// GoPlus does not tell whether a capability survives renouncement, so its
+// capabilities are assumed to be disabled by it, unless the contract has a
+// hidden owner or can take back ownership, in which case renouncing does not
+// give up control.
+func newTradingRestrictionsFromGoPlus(r models.ResponseWrapperTokenSecurityResultAnon) *TradingRestrictions {
+	if r.TokenName == "" && r.SlippageModifiable == "" && r.IsAntiWhale == "" {
+		return nil
+	}
+	disabledByRenounce := r.HiddenOwner != "1" && r.CanTakeBackOwnership != "1"
+	return &TradingRestrictions{
+		UpdateFees:       newRestriction(r.SlippageModifiable == "1", disabledByRenounce),
+		PersonalFees:     newRestriction(r.PersonalSlippageModifiable == "1", disabledByRenounce),
+		UpdateAntiWhale:  newRestriction(r.AntiWhaleModifiable == "1", disabledByRenounce),
+		Blacklist:        newRestriction(r.IsBlacklisted == "1", disabledByRenounce),
+		Whitelist:        newRestriction(r.IsWhitelisted == "1", disabledByRenounce),
+		PauseTrading:     newRestriction(r.TransferPausable == "1", disabledByRenounce),
+		AntiWhaleInForce: r.IsAntiWhale == "1",
+		TradingCooldown:  r.TradingCooldown == "1",
+	}
+}
+
+// mergeTradingRestrictions merges several restriction sections, keeping the
+// worst case of every flag, and resolves which capabilities are exercisable
+// given ownership. It returns nil when no section is given.
+func mergeTradingRestrictions(ownership *Ownership, sections ...*TradingRestrictions) *TradingRestrictions {
+	var merged *TradingRestrictions
+	for _, section := range sections {
+		if section == nil {
+			continue
+		}
+		if merged == nil {
+			merged = &TradingRestrictions{}
+		}
+		current := section.restrictions()
+		for i, entry := range merged.restrictions() {
+			entry.restriction.Capable = entry.restriction.Capable || current[i].restriction.Capable
+			entry.restriction.ExercisableAfterRenounce = entry.restriction.ExercisableAfterRenounce || current[i].restriction.ExercisableAfterRenounce
+		}
+		merged.AntiWhaleInForce = merged.AntiWhaleInForce || section.AntiWhaleInForce
+		merged.TradingCooldown = merged.TradingCooldown || section.TradingCooldown
+		if merged.ModifiedTransferFunctions == "" {
+			merged.ModifiedTransferFunctions = section.ModifiedTransferFunctions
+		}
+	}
+	if merged == nil {
+		return nil
+	}
+
+	renounced := ownership != nil && ownership.Renounced
+	for _, entry := range merged.restrictions() {
+		r := entry.restriction
+		r.Exercisable = r.Capable && (!renounced || r.ExercisableAfterRenounce)
+	}
+
+	return merged
+}
+
+// restrictionSeverities is the severity of each exercisable restriction.
+// Restrictions already covered by the top-level flags are not repeated.
+var restrictionSeverities = map[string]Severity{
+	"update_fees":       SeverityMedium,
+	"personal_fees":     SeverityHigh,
+	"update_max_wallet": SeverityLow,
+	"update_max_tx":     SeverityLow,
+	"update_anti_whale": SeverityLow,
+	"multi_blacklist":   SeverityMedium,
+	"modified_transfer": SeverityMedium,
+}
+
+// assessRestrictions adds a risk factor for every exercisable restriction.
+func (v *RiskVerdict) assessRestrictions(r *TradingRestrictions) {
+	for _, entry := range r.restrictions() {
+		severity, ok := restrictionSeverities[entry.name]
+		if !ok || !entry.restriction.Exercisable {
+			continue
+		}
+		reason := fmt.Sprintf("owner can still use the %s capability", entry.name)
+		if entry.restriction.ExercisableAfterRenounce {
+			reason += ", even after renouncing ownership"
+		}
+		v.add(entry.name, severity, reason)
+	}
+}
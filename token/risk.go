@@ -0,0 +1,245 @@
+package token
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Severity ranks how dangerous a risk factor is.
+type Severity string
+
+// Severities, from least to most dangerous.
+const (
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// severityWeights is the score each severity adds to a verdict.
+var severityWeights = map[Severity]int{
+	SeverityLow:      5,
+	SeverityMedium:   15,
+	SeverityHigh:     30,
+	SeverityCritical: 60,
+}
+
+// Rank returns the position of the severity from 0 (unknown) to 4 (critical).
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh:
+		return 3
+	case SeverityCritical:
+		return 4
+	}
+	return 0
+}
+
+// Verdict levels.
+const (
+	VerdictSafe    = "safe"
+	VerdictCaution = "caution"
+	VerdictDanger  = "danger"
+	// VerdictUnknown is given when no provider reported data, or when some
+	// failed and the others found nothing wrong.
+	VerdictUnknown = "unknown"
+)
+
+// Score limits used to derive the verdict level.
+const (
+	maxRiskScore     = 100
+	cautionRiskScore = 15
+	dangerRiskScore  = 50
+)
+
+// RiskFactor represents one reason contributing to the verdict.
+type RiskFactor struct {
+	Code     string   `json:"code"`
+	Severity Severity `json:"severity"`
+	Reason   string   `json:"reason"`
+}
+
+// RiskVerdict represents the overall risk assessment of a token.
+type RiskVerdict struct {
+	Level   string       `json:"level"`
+	Score   int          `json:"score"`
+	Factors []RiskFactor `json:"factors,omitempty"`
+	// Incomplete is set when some providers reported no data.
+	Incomplete bool `json:"incomplete,omitempty"`
+	// MissingSources are the providers that reported no data.
+	MissingSources []string `json:"missing_sources,omitempty"`
+}
+
+// add records a risk factor.
+func (v *RiskVerdict) add(code string, severity Severity, reason string) {
+	v.Factors = append(v.Factors, RiskFactor{Code: code, Severity: severity, Reason: reason})
+}
+
+// AssessRisk derives a risk verdict from the unified token information.
+func AssessRisk(t *TokenInfo) *RiskVerdict {
+	limits := currentThresholds()
+	verdict := &RiskVerdict{}
+	for _, source := range []string{SourceGoPlus, SourceHoneypot, SourceQuickIntel} {
+		if !t.hasSource(source) {
+			verdict.MissingSources = append(verdict.MissingSources, source)
+		}
+	}
+	verdict.Incomplete = len(verdict.MissingSources) > 0
+	if len(t.Sources) == 0 {
+		verdict.Level = VerdictUnknown
+		return verdict
+	}
+
+	if t.IsHoneypot {
+		reason := "token was detected as a honeypot"
+		if t.HoneypotReason != "" {
+			reason += ": " + t.HoneypotReason
+		}
+		verdict.add("honeypot", SeverityCritical, reason)
+	}
+	if t.CannotSellAll {
+		verdict.add("cannot_sell_all", SeverityHigh, "holders cannot sell their whole balance")
+	}
+	if t.CannotBuy {
+		verdict.add("cannot_buy", SeverityMedium, "token cannot be bought")
+	}
+	if t.HiddenOwner {
+		verdict.add("hidden_owner", SeverityHigh, "contract has a hidden owner")
+	}
+	if t.CanTakeBackOwnership {
+		verdict.add("can_take_back_ownership", SeverityHigh, "ownership can be reclaimed after renouncement")
+	}
+	if t.OwnerChangeBalance {
+		verdict.add("owner_change_balance", SeverityHigh, "owner can change holder balances")
+	}
+	if t.IsMintable {
+		verdict.add("mintable", SeverityMedium, "supply can be minted")
+	}
+	if t.TransferPausable {
+		verdict.add("transfer_pausable", SeverityMedium, "transfers can be paused")
+	}
+	if t.IsBlacklisted {
+		verdict.add("blacklist", SeverityMedium, "contract can blacklist addresses")
+	}
+	if t.IsWhitelisted {
+		verdict.add("whitelist", SeverityLow, "contract has a whitelist")
+	} else if t.CanWhitelist {
+		verdict.add("whitelist", SeverityLow, "owner can whitelist addresses")
+	}
+	if t.ExternalCall {
+		verdict.add("external_call", SeverityMedium, "contract calls external contracts")
+	}
+	if t.TradingCooldown {
+		verdict.add("trading_cooldown", SeverityLow, "contract enforces a trading cooldown")
+	}
+	// An unverified source is only held against the token when a provider said so
+	if !t.IsOpenSource && t.isReported("is_open_source") {
+		verdict.add("closed_source", SeverityHigh, "contract source code is not verified")
+	}
+	if tax, ok := parseTax(t.SellTax); ok && atLeast(tax, limits.HighTax) {
+		verdict.add("high_sell_tax", SeverityHigh, fmt.Sprintf("sell tax is %.0f%%", tax*100))
+	}
+	if tax, ok := parseTax(t.BuyTax); ok && atLeast(tax, limits.HighTax) {
+		verdict.add("high_buy_tax", SeverityMedium, fmt.Sprintf("buy tax is %.0f%%", tax*100))
+	}
+	if tax, ok := parseTax(t.TransferTax); ok && atLeast(tax, limits.HighTax) {
+		verdict.add("high_transfer_tax", SeverityMedium, fmt.Sprintf("transfer tax is %.0f%%", tax*100))
+	}
+	if tax, ok := parseTax(t.PostReenableSellTax); ok && atLeast(tax, limits.HighTax) {
+		verdict.add("high_post_reenable_sell_tax", SeverityHigh, fmt.Sprintf("sell tax becomes %.0f%% once trading is re-enabled", tax*100))
+	}
+
+	if h := t.HolderAnalysis; h != nil {
+		switch {
+		case atLeast(h.FailedSellShare, limits.FailedSellShareCritical):
+			verdict.add("holders_unable_to_sell", SeverityCritical, fmt.Sprintf("%.0f%% of holders are unable to sell", h.FailedSellShare*100))
+		case atLeast(h.FailedSellShare, limits.FailedSellShareHigh):
+			verdict.add("holders_unable_to_sell", SeverityHigh, fmt.Sprintf("%.0f%% of holders are unable to sell", h.FailedSellShare*100))
+		}
+		if h.SiphonedDetected {
+			verdict.add("siphoned_wallets", SeverityHigh, fmt.Sprintf("tokens were siphoned from %d holder wallets", h.Siphoned))
+		}
+	}
+
+	if l := t.Liquidity; l != nil {
+		if below(l.LiquidityUSD, limits.MinLiquidityUSD) {
+			verdict.add("thin_liquidity", SeverityHigh, fmt.Sprintf("pair liquidity is $%.0f, below $%.0f", l.LiquidityUSD, limits.MinLiquidityUSD))
+		}
+		if l.CreatedAt != nil && below(l.AgeHours, limits.MinPairAgeHours) {
+			verdict.add("new_pair", SeverityMedium, fmt.Sprintf("pair was created %.1f hours ago", l.AgeHours))
+		}
+	}
+
+	if c := t.Concentration; c != nil {
+		if above(c.TopHoldersShare, limits.MaxTopHoldersShare) {
+			verdict.add("concentrated_holders", SeverityHigh, fmt.Sprintf("top %d holders own %.0f%% of the supply", topHolderCount, c.TopHoldersShare*100))
+		}
+		if above(c.CreatorShare, limits.MaxCreatorShare) {
+			verdict.add("creator_share", SeverityMedium, fmt.Sprintf("creator holds %.0f%% of the supply", c.CreatorShare*100))
+		}
+		if above(c.OwnerShare, limits.MaxOwnerShare) {
+			verdict.add("owner_share", SeverityMedium, fmt.Sprintf("owner holds %.0f%% of the supply", c.OwnerShare*100))
+		}
+		if len(c.LPHolders) > 0 {
+			if secured := c.LPLockedShare + c.LPBurnedShare; below(secured, limits.MinLPSecuredShare) {
+				verdict.add("lp_unlocked", SeverityHigh, fmt.Sprintf("only %.0f%% of liquidity is locked or burned", secured*100))
+			}
+		}
+	}
+
+	verdict.assessFindings(t.ScamFindings)
+
+	if t.TradingRestrictions != nil {
+		verdict.assessRestrictions(t.TradingRestrictions)
+	}
+
+	if u := t.Upgradeability; u != nil && u.Upgradeable() {
+		if t.Ownership == nil || !t.Ownership.Renounced {
+			verdict.add("upgradeable_with_owner", SeverityHigh, "contract is upgradeable and ownership is not renounced")
+		} else {
+			verdict.add("upgradeable", SeverityMedium, "contract is an upgradeable proxy")
+		}
+	}
+
+	verdict.finalize()
+
+	return verdict
+}
+
+// finalize computes the score and level from the recorded factors.
+func (v *RiskVerdict) finalize() {
+	critical := false
+	for _, factor := range v.Factors {
+		v.Score += severityWeights[factor.Severity]
+		critical = critical || factor.Severity == SeverityCritical
+	}
+	if v.Score > maxRiskScore {
+		v.Score = maxRiskScore
+	}
+
+	switch {
+	case critical || v.Score >= dangerRiskScore:
+		v.Level = VerdictDanger
+	case v.Score >= cautionRiskScore:
+		v.Level = VerdictCaution
+	case v.Incomplete:
+		// Missing providers may have found what the others did not
+		v.Level = VerdictUnknown
+	default:
+		v.Level = VerdictSafe
+	}
+}
+
+// parseTax parses a GoPlus-style tax fraction such as "0.05".
+func parseTax(value string) (float64, bool) {
+	tax, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, false
+	}
+	return tax, true
+}
@@ -0,0 +1,94 @@
+package token
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/s-Amine/token-scan/scanners/quickintel"
+)
+
+// Finding represents a piece of scam intelligence about the contract.
+type Finding struct {
+	Kind     string   `json:"kind"`
+	Severity Severity `json:"severity"`
+	Summary  string   `json:"summary"`
+	// Items are the matched templates, functions or addresses behind the finding.
+	Items  []string `json:"items,omitempty"`
+	Source string   `json:"source"`
+}
+
+// Scam finding kinds.
+const (
+	FindingMatchedScam          = "matched_scam"
+	FindingScamFunctions        = "scam_functions"
+	FindingScamWalletFunding    = "scam_wallet_funding"
+	FindingObfuscatedAddress    = "obfuscated_address"
+	FindingSuspiciousFunctions  = "suspicious_functions"
+	FindingGeneralVulnerability = "general_vulnerability"
+)
+
+// newScamFindings extracts the scam intelligence of a QuickIntel audit.
+func newScamFindings(response quickintel.QuickIntelResponse) []Finding {
+	audit := response.QuickiAudit
+	var findings []Finding
+
+	add := func(present bool, kind string, severity Severity, summary, items string) {
+		list := splitList(items)
+		if !present && len(list) == 0 {
+			return
+		}
+		findings = append(findings, Finding{
+			Kind:     kind,
+			Severity: severity,
+			Summary:  summary,
+			Items:    list,
+			Source:   SourceQuickIntel,
+		})
+	}
+
+	add(audit.HasScams, FindingMatchedScam, SeverityCritical, "contract matches known scam templates", audit.MatchedScams)
+	add(audit.ScamFuncs != "", FindingScamFunctions, SeverityHigh, "contract contains functions used by known scams", audit.ScamFuncs)
+	add(audit.HasKnownScamWalletFunding, FindingScamWalletFunding, SeverityHigh, "deployer was funded by wallets linked to known scams", audit.KnownScamWalletFunding)
+	add(audit.HasObfuscatedAddressRisk, FindingObfuscatedAddress, SeverityHigh, "contract hides addresses in obfuscated form", audit.ObfuscatedAddressList)
+	add(audit.HasSuspiciousFunctions, FindingSuspiciousFunctions, SeverityMedium, "contract contains suspicious functions", audit.SuspiciousFuncs)
+	add(audit.HasGeneralVulnerabilities, FindingGeneralVulnerability, SeverityMedium, "contract has general vulnerabilities", audit.GeneralVulnerabilities)
+
+	return findings
+}
+
+// splitList parses a provider list field, which is either a JSON array or a
+// comma, semicolon or newline separated string.
+func splitList(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "null" {
+		return nil
+	}
+	if strings.HasPrefix(value, "[") {
+		var items []string
+		if err := json.Unmarshal([]byte(value), &items); err == nil {
+			return items
+		}
+	}
+
+	var items []string
+	for _, item := range strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	}) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// assessFindings adds a risk factor for every scam finding.
+func (v *RiskVerdict) assessFindings(findings []Finding) {
+	for _, finding := range findings {
+		reason := finding.Summary
+		if len(finding.Items) > 0 {
+			reason = fmt.Sprintf("%s: %s", reason, strings.Join(finding.Items, ", "))
+		}
+		v.add(finding.Kind, finding.Severity, reason)
+	}
+}
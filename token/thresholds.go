@@ -0,0 +1,124 @@
+package token
+
+import "sync"
+
+// Thresholds holds the limits used by AssessRisk. A nil limit keeps its
+// default from DefaultThresholds, and a zero or negative limit turns its check
+// off.
+type Thresholds struct {
+	// HighTax is the buy or sell tax fraction considered high.
+	HighTax *float64 `json:"high_tax,omitempty"`
+	// FailedSellShareHigh and FailedSellShareCritical are the shares of holders
+	// unable to sell that raise a high or critical finding.
+	FailedSellShareHigh     *float64 `json:"failed_sell_share_high,omitempty"`
+	FailedSellShareCritical *float64 `json:"failed_sell_share_critical,omitempty"`
+	// MinLiquidityUSD is the liquidity below which a pair is considered thin.
+	MinLiquidityUSD *float64 `json:"min_liquidity_usd,omitempty"`
+	// MinPairAgeHours is the age below which a pair is considered very new.
+	MinPairAgeHours *float64 `json:"min_pair_age_hours,omitempty"`
+	// MaxTopHoldersShare is the share of supply held by the ten largest
+	// holders above which the supply is considered concentrated.
+	MaxTopHoldersShare *float64 `json:"max_top_holders_share,omitempty"`
+	// MaxCreatorShare and MaxOwnerShare are the shares of supply the creator
+	// and owner may hold before being flagged.
+	MaxCreatorShare *float64 `json:"max_creator_share,omitempty"`
+	MaxOwnerShare   *float64 `json:"max_owner_share,omitempty"`
+	// MinLPSecuredShare is the share of LP tokens that must be locked or burned.
+	MinLPSecuredShare *float64 `json:"min_lp_secured_share,omitempty"`
+}
+
+// limits are thresholds with every limit resolved.
+type limits struct {
+	HighTax                 float64
+	FailedSellShareHigh     float64
+	FailedSellShareCritical float64
+	MinLiquidityUSD         float64
+	MinPairAgeHours         float64
+	MaxTopHoldersShare      float64
+	MaxCreatorShare         float64
+	MaxOwnerShare           float64
+	MinLPSecuredShare       float64
+}
+
+// DefaultThresholds are the thresholds used when none are configured.
+var DefaultThresholds = Thresholds{
+	HighTax:                 Limit(0.1),
+	FailedSellShareHigh:     Limit(0.1),
+	FailedSellShareCritical: Limit(0.5),
+	MinLiquidityUSD:         Limit(10000),
+	MinPairAgeHours:         Limit(24),
+	MaxTopHoldersShare:      Limit(0.5),
+	MaxCreatorShare:         Limit(0.05),
+	MaxOwnerShare:           Limit(0.05),
+	MinLPSecuredShare:       Limit(0.9),
+}
+
+// Limit returns a pointer to value, for setting a Thresholds limit.
+func Limit(value float64) *float64 {
+	return &value
+}
+
+var (
+	thresholdsMu sync.RWMutex
+	thresholds   = DefaultThresholds.resolve()
+)
+
+// SetThresholds sets the thresholds used by subsequent assessments.
+func SetThresholds(t Thresholds) {
+	thresholdsMu.Lock()
+	defer thresholdsMu.Unlock()
+
+	thresholds = t.resolve()
+}
+
+// currentThresholds returns the configured limits.
+func currentThresholds() limits {
+	thresholdsMu.RLock()
+	defer thresholdsMu.RUnlock()
+
+	return thresholds
+}
+
+// resolve takes the limits that are not set from DefaultThresholds.
+func (t Thresholds) resolve() limits {
+	return limits{
+		HighTax:                 limitOrDefault(t.HighTax, DefaultThresholds.HighTax),
+		FailedSellShareHigh:     limitOrDefault(t.FailedSellShareHigh, DefaultThresholds.FailedSellShareHigh),
+		FailedSellShareCritical: limitOrDefault(t.FailedSellShareCritical, DefaultThresholds.FailedSellShareCritical),
+		MinLiquidityUSD:         limitOrDefault(t.MinLiquidityUSD, DefaultThresholds.MinLiquidityUSD),
+		MinPairAgeHours:         limitOrDefault(t.MinPairAgeHours, DefaultThresholds.MinPairAgeHours),
+		MaxTopHoldersShare:      limitOrDefault(t.MaxTopHoldersShare, DefaultThresholds.MaxTopHoldersShare),
+		MaxCreatorShare:         limitOrDefault(t.MaxCreatorShare, DefaultThresholds.MaxCreatorShare),
+		MaxOwnerShare:           limitOrDefault(t.MaxOwnerShare, DefaultThresholds.MaxOwnerShare),
+		MinLPSecuredShare:       limitOrDefault(t.MinLPSecuredShare, DefaultThresholds.MinLPSecuredShare),
+	}
+}
+
+// atLeast reports whether value reaches limit, a limit that is not positive
+// never being reached.
+func atLeast(value, limit float64) bool {
+	return limit > 0 && value >= limit
+}
+
+// above reports whether value exceeds limit, a limit that is not positive
+// never being exceeded.
+func above(value, limit float64) bool {
+	return limit > 0 && value > limit
+}
+
+// below reports whether value falls short of limit, a limit that is not
+// positive never being missed.
+func below(value, limit float64) bool {
+	return limit > 0 && value < limit
+}
+
+// limitOrDefault returns the configured limit, or the default when it is not set.
+func limitOrDefault(limit, defaultLimit *float64) float64 {
+	if limit != nil {
+		return *limit
+	}
+	if defaultLimit != nil {
+		return *defaultLimit
+	}
+	return 0
+}
@@ -0,0 +1,110 @@
+package token
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetThresholds(t *testing.T) {
+	r := replayScan(t, honeypotToken)
+	unified := UnifyTokenInfo(InitTokenInfoFromGoPlus(r.goPlus), InitTokenInfoFromHoneypotResponse(r.honeypot, scanTime), InitTokenInfoFromQuickIntelResponse(r.quickIntel))
+	t.Cleanup(func() { SetThresholds(Thresholds{}) })
+
+	tests := []struct {
+		name       string
+		thresholds Thresholds
+		present    []string
+		absent     []string
+	}{
+		{
+			name:    "defaults",
+			present: []string{"thin_liquidity", "new_pair"},
+		},
+		{
+			name:       "zero turns checks off",
+			thresholds: Thresholds{MinLiquidityUSD: Limit(0), MinPairAgeHours: Limit(0)},
+			absent:     []string{"thin_liquidity", "new_pair"},
+		},
+		{
+			name:       "unset limits keep their defaults",
+			thresholds: Thresholds{MinLiquidityUSD: Limit(0)},
+			present:    []string{"new_pair"},
+			absent:     []string{"thin_liquidity"},
+		},
+	}
+	for _, tt := range tests {
+		SetThresholds(tt.thresholds)
+		verdict := AssessRisk(unified)
+		for _, code := range tt.present {
+			if !hasFactor(verdict, code) {
+				t.Errorf("%s: missing risk factor %s in %+v", tt.name, code, verdict.Factors)
+			}
+		}
+		for _, code := range tt.absent {
+			if hasFactor(verdict, code) {
+				t.Errorf("%s: got risk factor %s, want it turned off", tt.name, code)
+			}
+		}
+	}
+}
+
+func TestThresholdsTurnedOff(t *testing.T) {
+	created := scanTime.Add(-time.Hour)
+	info := &TokenInfo{
+		BuyTax:         "0.01",
+		SellTax:        "0.02",
+		HolderAnalysis: &HolderAnalysis{FailedSellShare: 0.01},
+		Liquidity:      &Liquidity{LiquidityUSD: 500, CreatedAt: &created, AgeHours: 1},
+		Concentration: &Concentration{
+			TopHoldersShare: 0.2,
+			CreatorShare:    0.01,
+			OwnerShare:      0.01,
+			LPHolders:       []Holder{{Address: "0x1", Share: 0.5}},
+			LPLockedShare:   0.1,
+		},
+	}
+	for _, source := range []string{SourceGoPlus, SourceHoneypot, SourceQuickIntel} {
+		info.addSource(source)
+	}
+	t.Cleanup(func() { SetThresholds(Thresholds{}) })
+
+	codes := []string{"high_buy_tax", "high_sell_tax", "holders_unable_to_sell", "thin_liquidity", "new_pair", "concentrated_holders", "creator_share", "owner_share", "lp_unlocked"}
+	for _, limit := range []float64{0, -1} {
+		SetThresholds(Thresholds{
+			HighTax:                 Limit(limit),
+			FailedSellShareHigh:     Limit(limit),
+			FailedSellShareCritical: Limit(limit),
+			MinLiquidityUSD:         Limit(limit),
+			MinPairAgeHours:         Limit(limit),
+			MaxTopHoldersShare:      Limit(limit),
+			MaxCreatorShare:         Limit(limit),
+			MaxOwnerShare:           Limit(limit),
+			MinLPSecuredShare:       Limit(limit),
+		})
+		verdict := AssessRisk(info)
+		for _, code := range codes {
+			if hasFactor(verdict, code) {
+				t.Errorf("limits of %v: got risk factor %s, want every threshold check off", limit, code)
+			}
+		}
+	}
+
+	// Small positive limits still flag every value reaching them
+	SetThresholds(Thresholds{
+		HighTax:                 Limit(0.001),
+		FailedSellShareHigh:     Limit(0.001),
+		FailedSellShareCritical: Limit(0.9),
+		MinLiquidityUSD:         Limit(1000),
+		MinPairAgeHours:         Limit(2),
+		MaxTopHoldersShare:      Limit(0.001),
+		MaxCreatorShare:         Limit(0.001),
+		MaxOwnerShare:           Limit(0.001),
+		MinLPSecuredShare:       Limit(0.2),
+	})
+	verdict := AssessRisk(info)
+	for _, code := range codes {
+		if !hasFactor(verdict, code) {
+			t.Errorf("small limits: missing risk factor %s in %+v", code, verdict.Factors)
+		}
+	}
+}
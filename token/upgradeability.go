@@ -0,0 +1,63 @@
+package token
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Upgradeability represents whether the contract logic can be replaced,
+// merged across providers. Worst case wins for every flag.
+type Upgradeability struct {
+	IsProxy bool `json:"is_proxy"`
+	// HasProxyCalls reports whether the contract delegates calls to another contract.
+	HasProxyCalls  bool           `json:"has_proxy_calls"`
+	Implementation string         `json:"implementation,omitempty"`
+	Disagreements  []Disagreement `json:"disagreements,omitempty"`
+}
+
+// Upgradeable reports whether the contract logic can be swapped out.
+func (u *Upgradeability) Upgradeable() bool {
+	return u.IsProxy || u.HasProxyCalls
+}
+
+// reconcileUpgradeability merges provider claims into one upgradeability
+// section. It returns nil when no provider reports proxy information.
+func reconcileUpgradeability(claims []*claim) *Upgradeability {
+	u := &Upgradeability{}
+	proxies := make(map[string]string)
+	implementations := make(map[string]string)
+	reported := false
+
+	for _, claim := range claims {
+		if claim == nil {
+			continue
+		}
+		if claim.isProxy != nil {
+			reported = true
+			proxies[claim.source] = strconv.FormatBool(*claim.isProxy)
+			u.IsProxy = u.IsProxy || *claim.isProxy
+		}
+		if claim.hasProxyCalls != nil {
+			reported = true
+			u.HasProxyCalls = u.HasProxyCalls || *claim.hasProxyCalls
+		}
+		if claim.implementation != "" {
+			implementations[claim.source] = strings.ToLower(claim.implementation)
+			if u.Implementation == "" {
+				u.Implementation = claim.implementation
+			}
+		}
+	}
+	if !reported && len(implementations) == 0 {
+		return nil
+	}
+	// An implementation address is only reported for proxies.
+	if u.Implementation != "" {
+		u.IsProxy = true
+	}
+
+	u.Disagreements = appendDisagreement(u.Disagreements, "is_proxy", proxies)
+	u.Disagreements = appendDisagreement(u.Disagreements, "implementation", implementations)
+
+	return u
+}
@@ -0,0 +1,33 @@
+package transport
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	mu           sync.RWMutex
+	roundTripper http.RoundTripper = http.DefaultTransport
+)
+
+// SetRoundTripper replaces the round tripper used by every scanner.
+// Passing nil restores http.DefaultTransport.
+func SetRoundTripper(rt http.RoundTripper) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	roundTripper = rt
+}
+
+// Client returns an HTTP client using the configured round tripper.
+// A zero timeout means no timeout.
+func Client(timeout time.Duration) *http.Client {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return &http.Client{Transport: roundTripper, Timeout: timeout}
+}
@@ -0,0 +1,355 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Fixture represents a recorded provider request/response pair.
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+// FixtureRequest represents the recorded request.
+type FixtureRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// FixtureResponse represents the recorded response.
+type FixtureResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body"`
+}
+
+// fixtureName returns the file name a request is stored under.
+// The name combines the host with a digest of the method, URL and body so
+// that scans of different tokens produce distinct files.
+func fixtureName(method, url, body string) string {
+	sum := sha256.Sum256([]byte(method + " " + url + "\n" + body))
+	host := url
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexAny(host, "/?"); i >= 0 {
+		host = host[:i]
+	}
+	host = strings.NewReplacer(":", "_", ".", "_").Replace(host)
+	return fmt.Sprintf("%s_%s.json", host, hex.EncodeToString(sum[:8]))
+}
+
+// redacted replaces the credentials removed from fixtures.
+const redacted = "REDACTED"
+
+// sensitiveFields are the normalized names of the body fields and URL query
+// parameters holding credentials, such as the app key, signature and access
+// token of the GoPlus access token exchange.
+var sensitiveFields = map[string]bool{
+	"accesstoken":   true,
+	"apikey":        true,
+	"appkey":        true,
+	"appsecret":     true,
+	"authorization": true,
+	"password":      true,
+	"refreshtoken":  true,
+	"secret":        true,
+	"sign":          true,
+	"signature":     true,
+}
+
+// normalizeName lowercases a field or parameter name and removes its dashes
+// and underscores.
+func normalizeName(name string) string {
+	return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(name))
+}
+
+// isSensitive reports whether a field or parameter name holds credentials,
+// ignoring case, dashes and underscores.
+func isSensitive(name string) bool {
+	return sensitiveFields[normalizeName(name)]
+}
+
+// redactURL masks the sensitive query parameters of rawURL.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	query := u.Query()
+	changed := false
+	for name := range query {
+		if isSensitive(name) {
+			query.Set(name, redacted)
+			changed = true
+		}
+	}
+	if !changed {
+		return rawURL
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// redactBody masks the string values of the sensitive fields of a JSON body,
+// at any depth. Bodies that are not JSON or hold no credentials are returned
+// unchanged.
+func redactBody(body string) string {
+	var value interface{}
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil || !redactValue(value) {
+		return body
+	}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return body
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// redactValue masks the sensitive fields of a decoded JSON value in place and
+// reports whether it changed anything.
+func redactValue(value interface{}) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			if _, ok := field.(string); ok && isSensitive(name) {
+				v[name] = redacted
+				changed = true
+				continue
+			}
+			if redactValue(field) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if redactValue(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// volatileFields are the normalized names of the request body fields that
+// change on every call, such as the timestamp of the signed GoPlus access
+// token exchange. They are left out when matching recorded requests.
+var volatileFields = map[string]bool{
+	"nonce":     true,
+	"time":      true,
+	"timestamp": true,
+}
+
+// matchingBody returns the form of a redacted request body compared with the
+// recorded ones: JSON bodies without their volatile fields.
+func matchingBody(body string) string {
+	var value interface{}
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil || !dropVolatile(value) {
+		return body
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return string(data)
+}
+
+// dropVolatile deletes the volatile fields of a decoded JSON value in place
+// and reports whether it changed anything.
+func dropVolatile(value interface{}) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			if volatileFields[normalizeName(name)] {
+				delete(v, name)
+				changed = true
+				continue
+			}
+			if dropVolatile(field) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if dropVolatile(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// readRequestBody reads and restores the body of req.
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return string(body), nil
+}
+
+// toResponse builds an *http.Response from the recorded response.
+func (f *Fixture) toResponse(req *http.Request) *http.Response {
+	header := f.Response.Header
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Response.StatusCode, http.StatusText(f.Response.StatusCode)),
+		StatusCode:    f.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header.Clone(),
+		Body:          ioutil.NopCloser(strings.NewReader(f.Response.Body)),
+		ContentLength: int64(len(f.Response.Body)),
+		Request:       req,
+	}
+}
+
+// Recorder is a round tripper that forwards requests and saves every
+// request/response pair as a fixture file in Dir.
+type Recorder struct {
+	Dir  string
+	Next http.RoundTripper
+}
+
+// NewRecorder creates a Recorder writing to dir. A nil next uses http.DefaultTransport.
+func NewRecorder(dir string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{Dir: dir, Next: next}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(resBody))
+
+	// Never persist credentials or cookies handed out by the provider.
+	fixture := Fixture{
+		Request: FixtureRequest{
+			Method: req.Method,
+			URL:    redactURL(req.URL.String()),
+			Body:   redactBody(reqBody),
+		},
+		Response: FixtureResponse{
+			StatusCode: res.StatusCode,
+			Header:     res.Header.Clone(),
+			Body:       redactBody(string(resBody)),
+		},
+	}
+	fixture.Response.Header.Del("Set-Cookie")
+
+	if err := r.save(fixture); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// save writes fixture to the recorder directory.
+func (r *Recorder) save(fixture Fixture) error {
+	if err := os.MkdirAll(r.Dir, 0o755); err != nil {
+		return fmt.Errorf("error creating fixture directory: %v", err)
+	}
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling fixture: %v", err)
+	}
+	name := fixtureName(fixture.Request.Method, fixture.Request.URL, fixture.Request.Body)
+	if err := os.WriteFile(filepath.Join(r.Dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("error writing fixture: %v", err)
+	}
+	return nil
+}
+
+// Replayer is a round tripper serving responses from fixture files
+// previously written by a Recorder. It never touches the network.
+type Replayer struct {
+	fixtures []Fixture
+}
+
+// NewReplayer loads every fixture file in dir.
+func NewReplayer(dir string) (*Replayer, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	replayer := &Replayer{}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading fixture: %v", err)
+		}
+		var fixture Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return nil, fmt.Errorf("error parsing fixture %s: %v", path, err)
+		}
+		replayer.fixtures = append(replayer.fixtures, fixture)
+	}
+
+	return replayer, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+// Requests are matched on method, URL and body, with credentials masked as
+// they were when recorded and volatile fields, such as the timestamp of
+// signed GoPlus access token exchanges, left out. A request no fixture
+// matches fails rather than being answered for another one.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	body = matchingBody(redactBody(body))
+	url := redactURL(req.URL.String())
+
+	for i := range r.fixtures {
+		fixture := &r.fixtures[i]
+		if fixture.Request.Method == req.Method && fixture.Request.URL == url && matchingBody(fixture.Request.Body) == body {
+			return fixture.toResponse(req), nil
+		}
+	}
+
+	return nil, fmt.Errorf("no fixture recorded for %s %s", req.Method, url)
+}
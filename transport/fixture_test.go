@@ -0,0 +1,150 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// accessTokenResponse is the answer of the fake GoPlus access token exchange.
+const accessTokenResponse = `{"code":1,"message":"OK","result":{"access_token":"live-access-token","expires_in":3600}}`
+
+func TestRecorderRedactsCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/token" {
+			io.WriteString(w, accessTokenResponse)
+			return
+		}
+		io.WriteString(w, `{"code":1,"message":"OK","result":{}}`)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	client := &http.Client{Transport: NewRecorder(dir, nil)}
+
+	res, err := client.Post(server.URL+"/api/v1/token", "application/json", strings.NewReader(`{"app_key":"live-app-key","sign":"live-signature","time":1700000000}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	if string(body) != accessTokenResponse {
+		t.Errorf("got response %s, want the access token unredacted", body)
+	}
+	if res, err = client.Get(server.URL + "/api/v1/token_security/1?contract_addresses=0x1&api_key=live-api-key"); err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(paths) != 2 {
+		t.Fatalf("got %d fixtures, want 2", len(paths))
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{"live-app-key", "live-signature", "live-access-token", "live-api-key"} {
+			if strings.Contains(string(data), secret) {
+				t.Errorf("%s holds %s:\n%s", filepath.Base(path), secret, data)
+			}
+		}
+		if !strings.Contains(string(data), redacted) {
+			t.Errorf("%s has no redacted field:\n%s", filepath.Base(path), data)
+		}
+	}
+
+	// The redacted fixtures are served back to requests holding other credentials
+	replayer, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: replayer}
+	res, err = client.Post(server.URL+"/api/v1/token", "application/json", strings.NewReader(`{"app_key":"other-app-key","sign":"other-signature","time":1700000060}`))
+	if err != nil {
+		t.Fatalf("replaying the access token exchange: %v", err)
+	}
+	res.Body.Close()
+	if res, err = client.Get(server.URL + "/api/v1/token_security/1?contract_addresses=0x1&api_key=other-api-key"); err != nil {
+		t.Fatalf("replaying the token security request: %v", err)
+	}
+	res.Body.Close()
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"app_key":"k","time":1}`, `{"app_key":"REDACTED","time":1}`},
+		{`{"result":{"access_token":"t","expires_in":3600}}`, `{"result":{"access_token":"REDACTED","expires_in":3600}}`},
+		{`{"token":{"name":"Fake","symbol":"FAKE"}}`, `{"token":{"name":"Fake","symbol":"FAKE"}}`},
+		{`{"token_name":"Fake","sign":"<s>"}`, `{"sign":"REDACTED","token_name":"Fake"}`},
+		{`not json`, `not json`},
+	}
+	for _, tt := range tests {
+		if got := redactBody(tt.body); got != tt.want {
+			t.Errorf("redactBody(%s) = %s, want %s", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestReplayerMatchesBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		io.WriteString(w, `{"audited":`+string(body)+`}`)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	recorder := &http.Client{Transport: NewRecorder(dir, nil)}
+	res, err := recorder.Post(server.URL+"/api/quicki/getquickiauditfull", "application/json", strings.NewReader(`{"chain":"eth","tokenAddress":"0x1","tier":"basic"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	replayer, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: replayer}
+	res, err = client.Post(server.URL+"/api/quicki/getquickiauditfull", "application/json", strings.NewReader(`{"chain":"eth","tokenAddress":"0x1","tier":"basic"}`))
+	if err != nil {
+		t.Fatalf("replaying the recorded audit: %v", err)
+	}
+	res.Body.Close()
+
+	// Another token's audit is not served for an unrecorded body
+	for _, body := range []string{
+		`{"chain":"eth","tokenAddress":"0x2","tier":"basic"}`,
+		`{"chain":"eth","tokenAddress":"0x1","tier":"premium"}`,
+	} {
+		if res, err := client.Post(server.URL+"/api/quicki/getquickiauditfull", "application/json", strings.NewReader(body)); err == nil {
+			res.Body.Close()
+			t.Errorf("replaying unrecorded body %s: got status %d, want an error", body, res.StatusCode)
+		}
+	}
+}
+
+func TestMatchingBody(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"app_key":"REDACTED","sign":"REDACTED","time":1700000000}`, `{"app_key":"REDACTED","sign":"REDACTED"}`},
+		{`{"request":{"Timestamp":1,"id":2}}`, `{"request":{"id":2}}`},
+		{`{"chain":"eth","tokenAddress":"0x1"}`, `{"chain":"eth","tokenAddress":"0x1"}`},
+		{`not json`, `not json`},
+	}
+	for _, tt := range tests {
+		if got := matchingBody(tt.body); got != tt.want {
+			t.Errorf("matchingBody(%s) = %s, want %s", tt.body, got, tt.want)
+		}
+	}
+}
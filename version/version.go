@@ -0,0 +1,19 @@
+package version
+
+import "runtime/debug"
+
+// Version is the tool version. Release builds set it with
+// -ldflags "-X github.com/s-Amine/token-scan/version.Version=<version>".
+var Version = ""
+
+// String returns Version, falling back to the module version recorded by
+// go install, or "dev" for source builds.
+func String() string {
+	if Version != "" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"runtime"
+
+	"github.com/s-Amine/token-scan/envelope"
+	"github.com/s-Amine/token-scan/version"
+)
+
+// versionInfo is the output of the version command.
+type versionInfo struct {
+	Version       string `json:"version"`
+	SchemaVersion string `json:"schema_version"`
+	GoVersion     string `json:"go_version"`
+	Platform      string `json:"platform"`
+}
+
+// versionCommand implements the version command.
+func versionCommand(flags *flag.FlagSet) func(args []string) {
+	return func(args []string) {
+		info := versionInfo{
+			Version:       version.String(),
+			SchemaVersion: envelope.SchemaVersion,
+			GoVersion:     runtime.Version(),
+			Platform:      runtime.GOOS + "/" + runtime.GOARCH,
+		}
+		// The version is printed as text unless an output format is selected
+		if globals.output == "" {
+			fmt.Printf("token-scan %s (output schema %s, %s %s)\n", info.Version, info.SchemaVersion, info.GoVersion, info.Platform)
+			return
+		}
+		printResult(info)
+	}
+}
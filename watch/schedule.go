@@ -0,0 +1,239 @@
+package watch
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when the watchlist is scanned next.
+type Schedule interface {
+	// Next returns the first scan time strictly after t.
+	Next(t time.Time) time.Time
+}
+
+// interval is a schedule scanning at a fixed period.
+type interval time.Duration
+
+// Every returns a schedule scanning every d.
+func Every(d time.Duration) Schedule {
+	return interval(d)
+}
+
+// Next implements Schedule.
+func (i interval) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
+
+// ParseSchedule parses a duration such as "10m", "@every 10m", a cron
+// descriptor such as "@hourly" or a five-field cron expression.
+func ParseSchedule(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every"))); err == nil {
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid schedule %q: interval must be positive", spec)
+		}
+		return Every(d), nil
+	}
+	return ParseCron(spec)
+}
+
+// cronDescriptors are the predefined cron schedules.
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField describes the range and names of a cron field.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var (
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	dowField    = cronField{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}}
+)
+
+// cron is a schedule given by a five-field cron expression, evaluated in
+// local time.
+type cron struct {
+	minute, hour, dom, month, dow map[int]bool
+	// domAny and dowAny record unrestricted day fields: when both day fields
+	// are restricted, a day matching either one is scheduled.
+	domAny, dowAny bool
+}
+
+// ParseCron parses a cron expression of the form "minute hour day-of-month
+// month day-of-week". Fields accept *, values, ranges, lists and steps, and
+// month and day names; descriptors such as @daily are accepted too.
+func ParseCron(expr string) (Schedule, error) {
+	if descriptor, ok := cronDescriptors[strings.ToLower(strings.TrimSpace(expr))]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+
+	c := &cron{domAny: fields[2] == "*", dowAny: fields[4] == "*"}
+	var err error
+	if c.minute, err = minuteField.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if c.hour, err = hourField.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if c.dom, err = domField.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if c.month, err = monthField.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if c.dow, err = dowField.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	// Sunday is both 0 and 7.
+	if c.dow[7] {
+		c.dow[0] = true
+	}
+	if !c.dowAny || c.domAny {
+		return c, nil
+	}
+	for month := range c.month {
+		for day := range c.dom {
+			if day <= monthDays[month-1] {
+				return c, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("invalid cron expression %q: day of month never occurs in the selected months", expr)
+}
+
+// monthDays are the most days of each month, counting February 29.
+var monthDays = [12]int{31, 29, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+
+// parse returns the set of values selected by a comma-separated field.
+func (f cronField) parse(field string) (map[int]bool, error) {
+	values := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		if err := f.parsePart(part, values); err != nil {
+			return nil, fmt.Errorf("invalid cron %s %q: %v", f.name, field, err)
+		}
+	}
+	return values, nil
+}
+
+// parsePart adds the values selected by one element of a list to values.
+func (f cronField) parsePart(part string, values map[int]bool) error {
+	rangePart, step := part, 1
+	if i := strings.Index(part, "/"); i >= 0 {
+		rangePart = part[:i]
+		var err error
+		if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+			return fmt.Errorf("invalid step %q", part[i+1:])
+		}
+	}
+
+	low, high := f.min, f.max
+	switch {
+	case rangePart == "*":
+	case strings.Contains(rangePart, "-"):
+		bounds := strings.SplitN(rangePart, "-", 2)
+		var err error
+		if low, err = f.value(bounds[0]); err != nil {
+			return err
+		}
+		if high, err = f.value(bounds[1]); err != nil {
+			return err
+		}
+		if low > high {
+			return fmt.Errorf("range %q is reversed", rangePart)
+		}
+	default:
+		var err error
+		if low, err = f.value(rangePart); err != nil {
+			return err
+		}
+		// A single value with a step runs to the end of the range, as in "5/15".
+		if !strings.Contains(part, "/") {
+			high = low
+		}
+	}
+
+	for v := low; v <= high; v += step {
+		values[v] = true
+	}
+	return nil
+}
+
+// value parses a number or name within the field range.
+func (f cronField) value(text string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(text, name) {
+			return i + f.min, nil
+		}
+	}
+	v, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", text)
+	}
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next implements Schedule.
+func (c *cron) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	// Parsed expressions match at least every eight years, the longest gap
+	// between two February 29; give up after that rather than loop forever.
+	limit := t.AddDate(9, 0, 0)
+
+	for t.Before(limit) {
+		if !c.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches reports whether the day of t is scheduled.
+func (c *cron) dayMatches(t time.Time) bool {
+	dom, dow := c.dom[t.Day()], c.dow[int(t.Weekday())]
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	}
+	return dom || dow
+}
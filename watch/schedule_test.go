@@ -0,0 +1,162 @@
+package watch
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+// values returns the sorted values of a parsed field.
+func values(set map[int]bool) []int {
+	var list []int
+	for v := range set {
+		list = append(list, v)
+	}
+	sort.Ints(list)
+	return list
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseSchedule(t *testing.T) {
+	start := time.Date(2024, 6, 1, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"10m", start.Add(10 * time.Minute)},
+		{"@every 1h30m", start.Add(90 * time.Minute)},
+		{"@hourly", time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)},
+		{"@WEEKLY", time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Errorf("%s: %v", tt.spec, err)
+			continue
+		}
+		if got := schedule.Next(start); !got.Equal(tt.want) {
+			t.Errorf("%s: got next run %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+
+	for _, spec := range []string{"0s", "-5m", "@every -1m", "@fortnightly", ""} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("%q: got no error, want an invalid schedule", spec)
+		}
+	}
+}
+
+func TestParseCronFields(t *testing.T) {
+	tests := []struct {
+		expr  string
+		field func(c *cron) map[int]bool
+		want  []int
+	}{
+		{"5 * * * *", func(c *cron) map[int]bool { return c.minute }, []int{5}},
+		{"1,3,5 * * * *", func(c *cron) map[int]bool { return c.minute }, []int{1, 3, 5}},
+		{"*/15 * * * *", func(c *cron) map[int]bool { return c.minute }, []int{0, 15, 30, 45}},
+		{"5/20 * * * *", func(c *cron) map[int]bool { return c.minute }, []int{5, 25, 45}},
+		{"10-20/5 * * * *", func(c *cron) map[int]bool { return c.minute }, []int{10, 15, 20}},
+		{"0 9-12 * * *", func(c *cron) map[int]bool { return c.hour }, []int{9, 10, 11, 12}},
+		{"0 0,12 * * *", func(c *cron) map[int]bool { return c.hour }, []int{0, 12}},
+		{"0 0 1,15-17 * *", func(c *cron) map[int]bool { return c.dom }, []int{1, 15, 16, 17}},
+		{"0 0 * jan-mar *", func(c *cron) map[int]bool { return c.month }, []int{1, 2, 3}},
+		{"0 0 * Jun,DEC *", func(c *cron) map[int]bool { return c.month }, []int{6, 12}},
+		{"0 0 * */4 *", func(c *cron) map[int]bool { return c.month }, []int{1, 5, 9}},
+		{"0 0 * * mon-fri", func(c *cron) map[int]bool { return c.dow }, []int{1, 2, 3, 4, 5}},
+		{"0 0 * * sat,sun", func(c *cron) map[int]bool { return c.dow }, []int{0, 6}},
+		{"0 0 * * 7", func(c *cron) map[int]bool { return c.dow }, []int{0, 7}},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if got := values(tt.field(schedule.(*cron))); !equalInts(got, tt.want) {
+			t.Errorf("%s: got values %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	invalid := []string{
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"30-10 * * * *",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"* * * foo *",
+		"1,,2 * * * *",
+		"0 0 30 2 *",
+		"0 0 31 apr,jun,sep,nov *",
+	}
+	for _, expr := range invalid {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("%q: got no error, want an invalid expression", expr)
+		}
+	}
+
+	// Days that occur in some selected month, or are also scheduled by weekday
+	valid := []string{"0 0 29 2 *", "0 0 30,31 2,3 *", "0 0 30 2 mon"}
+	for _, expr := range valid {
+		if _, err := ParseCron(expr); err != nil {
+			t.Errorf("%q: %v", expr, err)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	date := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	// June 1 2024 is a Saturday
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"*/15 * * * *", date(2024, 6, 1, 10, 7).Add(30 * time.Second), date(2024, 6, 1, 10, 15)},
+		{"0 * * * *", date(2024, 6, 1, 10, 0), date(2024, 6, 1, 11, 0)},
+		{"59 23 * * *", date(2024, 6, 1, 23, 59), date(2024, 6, 2, 23, 59)},
+		{"30 9 * * mon-fri", date(2024, 6, 1, 12, 0), date(2024, 6, 3, 9, 30)},
+		{"0 0 13 * *", date(2024, 6, 1, 0, 0), date(2024, 6, 13, 0, 0)},
+		{"0 0 * * fri", date(2024, 6, 8, 0, 0), date(2024, 6, 14, 0, 0)},
+		// Restricting both day fields schedules days matching either one
+		{"0 0 13 * fri", date(2024, 6, 1, 0, 0), date(2024, 6, 7, 0, 0)},
+		{"0 0 13 * fri", date(2024, 6, 8, 0, 0), date(2024, 6, 13, 0, 0)},
+		{"0 12 31 * *", date(2024, 4, 1, 0, 0), date(2024, 5, 31, 12, 0)},
+		{"0 0 1 jan *", date(2024, 12, 31, 23, 59), date(2025, 1, 1, 0, 0)},
+		{"0 0 29 2 *", date(2024, 3, 1, 0, 0), date(2028, 2, 29, 0, 0)},
+		{"0 0 29 2 *", date(2096, 3, 1, 0, 0), date(2104, 2, 29, 0, 0)},
+	}
+	for _, tt := range tests {
+		schedule, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Errorf("%s: %v", tt.expr, err)
+			continue
+		}
+		if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+			t.Errorf("%s from %v: got %v, want %v", tt.expr, tt.from, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/s-Amine/token-scan/diff"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/token"
+)
+
+// DefaultMinSeverity is the lowest change severity alerted on by default.
+const DefaultMinSeverity = token.SeverityHigh
+
+// Alert reports the security-relevant changes of a watched token between
+// two scans.
+type Alert struct {
+	Address   string         `json:"address"`
+	ScannedAt time.Time      `json:"scanned_at"`
+	Severity  token.Severity `json:"severity"`
+	Verdict   string         `json:"verdict,omitempty"`
+	Changes   []diff.Change  `json:"changes"`
+}
+
+// Watcher rescans a watchlist of tokens on a schedule and compares each
+// result with the previous scan of the token.
+type Watcher struct {
+	Tokens   []string
+	Schedule Schedule
+	// MinSeverity is the lowest change severity alerted on; empty means
+	// DefaultMinSeverity.
+	MinSeverity token.Severity
+
+	// Scan scans a token; it defaults to a multiscan with raw responses, which
+	// lets incomplete scans be detected.
+	Scan func(address string) *multiscan.Result
+	// OnScan, when set, is called with every scan result.
+	OnScan func(address string, result *multiscan.Result)
+	// OnAlert is called when a token changed.
+	OnAlert func(alert Alert)
+	// OnError, when set, is called when a scan is incomplete and not compared.
+	OnError func(address string, err error)
+
+	previous map[string]*token.TokenInfo
+}
+
+// Seed sets the scan the first scan of a token is compared with, such as
+// its last scan from the history.
+func (w *Watcher) Seed(address string, info *token.TokenInfo) {
+	if w.previous == nil {
+		w.previous = map[string]*token.TokenInfo{}
+	}
+	w.previous[strings.ToLower(address)] = info
+}
+
+// Run scans the watchlist immediately and then on every scheduled time until
+// ctx is done. A scan in progress is completed before returning.
+func (w *Watcher) Run(ctx context.Context) error {
+	for {
+		for _, address := range w.Tokens {
+			if ctx.Err() != nil {
+				return nil
+			}
+			w.check(address)
+		}
+
+		next := w.Schedule.Next(time.Now())
+		if next.IsZero() {
+			return fmt.Errorf("schedule has no next run")
+		}
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// check scans a token and alerts on the changes since its previous scan.
+func (w *Watcher) check(address string) {
+	scan := w.Scan
+	if scan == nil {
+		scan = func(address string) *multiscan.Result {
+			return multiscan.ScanWithOptions(address, multiscan.Options{IncludeRaw: true})
+		}
+	}
+	result := scan(address)
+	scannedAt := time.Now().UTC()
+	if w.OnScan != nil {
+		w.OnScan(address, result)
+	}
+
+	// A provider failure empties its fields, which would read as changes.
+	if result.Raw != nil && len(result.Raw.Errors) > 0 {
+		if w.OnError != nil {
+			w.OnError(address, fmt.Errorf("incomplete scan, not compared: %s", providerErrors(result.Raw.Errors)))
+		}
+		return
+	}
+
+	key := strings.ToLower(address)
+	previous := w.previous[key]
+	w.Seed(address, result.Unified)
+	if previous == nil {
+		return
+	}
+
+	changes, err := diff.Tokens(previous, result.Unified)
+	if err != nil {
+		if w.OnError != nil {
+			w.OnError(address, err)
+		}
+		return
+	}
+
+	minSeverity := w.MinSeverity
+	if minSeverity == "" {
+		minSeverity = DefaultMinSeverity
+	}
+	alert := Alert{Address: address, ScannedAt: scannedAt}
+	if result.Unified.Risk != nil {
+		alert.Verdict = result.Unified.Risk.Level
+	}
+	for _, change := range changes {
+		if change.Severity.Rank() < minSeverity.Rank() {
+			continue
+		}
+		alert.Changes = append(alert.Changes, change)
+		if change.Severity.Rank() > alert.Severity.Rank() {
+			alert.Severity = change.Severity
+		}
+	}
+	if len(alert.Changes) > 0 && w.OnAlert != nil {
+		w.OnAlert(alert)
+	}
+}
+
+// providerErrors formats provider errors in provider order.
+func providerErrors(errs map[string]string) string {
+	providers := make([]string, 0, len(errs))
+	for provider := range errs {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+
+	parts := make([]string, len(providers))
+	for i, provider := range providers {
+		parts[i] = provider + ": " + errs[provider]
+	}
+	return strings.Join(parts, "; ")
+}
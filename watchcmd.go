@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/s-Amine/token-scan/alert"
+	"github.com/s-Amine/token-scan/chain"
+	"github.com/s-Amine/token-scan/history"
+	"github.com/s-Amine/token-scan/scanners/multiscan"
+	"github.com/s-Amine/token-scan/token"
+	"github.com/s-Amine/token-scan/watch"
+)
+
+// watchCommand implements the watch command. It rescans a watchlist on a
+// schedule and prints an alert for every security-relevant change, which is
+// also sent to the configured alert sinks.
+func watchCommand(flags *flag.FlagSet) func(args []string) {
+	tokens := flags.String("tokens", "", "Comma-separated token hashes to watch")
+	watchlist := flags.String("watchlist", "", "File listing token hashes to watch, one per line (- for stdin)")
+	scheduleSpec := flags.String("schedule", "5m", "Scan interval (e.g. 10m) or cron expression (e.g. \"*/15 * * * *\")")
+	minSeverity := flags.String("min-severity", string(watch.DefaultMinSeverity), "Lowest change severity to alert on: low, medium, high or critical")
+	noAlerts := flags.Bool("no-alerts", false, "Do not send alerts to the configured sinks")
+	noHistory := flags.Bool("no-history", false, "Do not record the scans in the history store")
+
+	return func(args []string) {
+		addresses, err := tokenList(*tokens, *watchlist)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		addresses = append(addresses, args...)
+		if len(addresses) == 0 {
+			usageError(flags, "-tokens, -watchlist or token hash arguments are required")
+		}
+
+		schedule, err := watch.ParseSchedule(*scheduleSpec)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		severity := parseSeverity(flags, *minSeverity)
+		c := globals.scanChain
+
+		cfg := loadConfig()
+		recording := !*noHistory && !cfg.History.Disabled
+		router := alertRouter(cfg.Alerts, *noAlerts)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		watcher := &watch.Watcher{
+			Tokens:      addresses,
+			Schedule:    schedule,
+			MinSeverity: severity,
+			Scan: func(address string) *multiscan.Result {
+				return multiscan.ScanWithOptions(address, multiscan.Options{Chain: c, IncludeRaw: true})
+			},
+			OnAlert: func(a watch.Alert) {
+				printResult(a)
+				routed := alert.FromWatch(a)
+				routed.Chain = c.Name
+				if err := router.Send(context.Background(), routed); err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				}
+			},
+			OnError: func(address string, err error) {
+				fmt.Fprintf(os.Stderr, "Error watching %s: %v\n", address, err)
+			},
+		}
+		if recording {
+			seedWatcher(watcher, cfg.History.Path, c)
+			watcher.OnScan = func(address string, result *multiscan.Result) {
+				verbosef("Scanned %s", address)
+				recordHistory(cfg.History.Path, "multiscan", c, address, result)
+			}
+		}
+
+		infof("Watching %d tokens", len(addresses))
+		if err := watcher.Run(ctx); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		infof("Stopped watching")
+	}
+}
+
+// tokenList collects the token hashes given as a comma-separated list and in
+// the list file, or stdin for "-", skipping blank lines and # comments.
+func tokenList(list, path string) ([]string, error) {
+	var addresses []string
+	for _, address := range strings.Split(list, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	if path == "" {
+		return addresses, nil
+	}
+
+	file := os.Stdin
+	if path != "-" {
+		var err error
+		if file, err = os.Open(path); err != nil {
+			return nil, fmt.Errorf("error opening token list: %v", err)
+		}
+		defer file.Close()
+	}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			addresses = append(addresses, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading token list: %v", err)
+	}
+	return addresses, nil
+}
+
+// seedWatcher compares the first scan of each token with its last complete
+// multiscan stored on chain c, so changes made while nothing was watching are
+// alerted too. Like the watcher, it skips scans where a provider reported no
+// data.
+func seedWatcher(watcher *watch.Watcher, path string, c chain.Chain) {
+	store, err := history.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading scan history: %v\n", err)
+		return
+	}
+	for _, address := range watcher.Tokens {
+		records, err := store.Query(history.Query{Address: address, Chain: c.ID, Mode: "multiscan"})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading scan history: %v\n", err)
+			return
+		}
+		for i := len(records) - 1; i >= 0; i-- {
+			info, err := records[i].TokenInfo()
+			if err == nil && completeScan(info) {
+				watcher.Seed(address, info)
+				break
+			}
+		}
+	}
+}
+
+// completeScan reports whether info holds a risk verdict based on the data of
+// every provider.
+func completeScan(info *token.TokenInfo) bool {
+	return info != nil && info.Risk != nil && !info.Risk.Incomplete && info.Risk.Level != token.VerdictUnknown
+}
+
+// parseSeverity parses the value of a -min-severity flag.
+func parseSeverity(flags *flag.FlagSet, value string) token.Severity {
+	severity := token.Severity(value)
+	if severity.Rank() == 0 {
+		usageError(flags, "invalid -min-severity %q", value)
+	}
+	return severity
+}
+
+// alertRouter builds the router of the configured alert sinks, or a router
+// without sinks when disabled.
+func alertRouter(cfg alert.Config, disabled bool) *alert.Router {
+	if disabled {
+		return alert.NewRouter(0)
+	}
+	router, err := alert.NewRouterFromConfig(cfg)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	verbosef("Routing alerts to %d sinks", router.Len())
+	return router
+}